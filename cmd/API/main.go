@@ -1,20 +1,36 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"log"
 	"net/http"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	_ "github.com/deividmendozatech-stack/wishlist/docs"
 
 	"github.com/deividmendozatech-stack/wishlist/internal/handler"
 	"github.com/deividmendozatech-stack/wishlist/internal/service"
 	"github.com/deividmendozatech-stack/wishlist/internal/storage"
+	"github.com/deividmendozatech-stack/wishlist/pkg/auth"
 
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+// @title Wishlist API
+// @version 1.0
+// @description REST API to manage book wishlists.
+// @BasePath /api
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and the JWT returned by /users/login.
+
 // main is the entry point of the Wishlist API.
 // It connects to the database, runs migrations, initializes repositories,
 // services, handlers, sets up routes, and starts the HTTP server.
@@ -32,46 +48,210 @@ func main() {
 	}
 
 	// Run migrations for User, Wishlist, and Book models
-	if err := db.AutoMigrate(&service.User{}, &service.Wishlist{}, &service.Book{}); err != nil {
+	if err := storage.Migrate(db); err != nil {
 		log.Fatal(err)
 	}
 
+	// Startup work (migrations, the enrichment and purge workers) runs without a deadline
+	ctx := context.Background()
+
 	// Initialize repositories
 	userRepo := storage.NewUserRepo(db)
 	wishlistRepo := storage.NewWishlistRepo(db)
 	bookRepo := storage.NewBookRepo(db)
 
+	// Hash any legacy plaintext passwords (BCRYPT_COST sets the work factor)
+	hasher := service.NewBcryptHasher(envInt("BCRYPT_COST", 0))
+	if n, err := service.MigratePlaintextPasswords(ctx, userRepo, hasher); err != nil {
+		log.Fatal(err)
+	} else if n > 0 {
+		log.Printf("hashed %d legacy plaintext password(s)", n)
+	}
+
+	// Grant admin privileges to the usernames listed in ADMIN_USERS (comma-separated)
+	for _, name := range strings.Split(os.Getenv("ADMIN_USERS"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			if err := userRepo.SetAdmin(ctx, name, true); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
 	// Initialize services (business logic layer)
-	userSvc := service.NewUserService(userRepo)
+	userSvc := service.NewUserServiceWithHasher(userRepo, hasher)
 	wishlistSvc := service.NewWishlistService(wishlistRepo)
-	bookSvc := service.NewBookService(bookRepo)
-	googleSvc := service.NewGoogleBooksService()
+	// Retries and the circuit breaker are disabled with a value of 0, which the
+	// options treat as "use the default", so it is translated to -1 here
+	maxRetries := envInt("GOOGLE_BOOKS_MAX_RETRIES", service.DefaultGoogleBooksMaxRetries)
+	if maxRetries == 0 {
+		maxRetries = -1
+	}
+	breakerThreshold := envInt("GOOGLE_BOOKS_BREAKER_THRESHOLD", service.DefaultGoogleBooksBreakerThreshold)
+	if breakerThreshold == 0 {
+		breakerThreshold = -1
+	}
+	googleSvc := service.NewGoogleBooksService(service.GoogleBooksOptions{
+		BaseURL:   os.Getenv("GOOGLE_BOOKS_BASE_URL"),
+		APIKey:    os.Getenv("GOOGLE_BOOKS_API_KEY"),
+		Timeout:   envDuration("GOOGLE_BOOKS_TIMEOUT", service.DefaultGoogleBooksTimeout),
+		UserAgent: os.Getenv("GOOGLE_BOOKS_USER_AGENT"),
+
+		MaxRetries:       maxRetries,
+		RetryBaseDelay:   envDuration("GOOGLE_BOOKS_RETRY_BASE_DELAY", service.DefaultGoogleBooksRetryBaseDelay),
+		RetryMaxDelay:    envDuration("GOOGLE_BOOKS_RETRY_MAX_DELAY", service.DefaultGoogleBooksRetryMaxDelay),
+		BreakerThreshold: breakerThreshold,
+		BreakerCooldown:  envDuration("GOOGLE_BOOKS_BREAKER_COOLDOWN", service.DefaultGoogleBooksBreakerCooldown),
+	})
+
+	// Cache Google Books responses (GOOGLE_BOOKS_CACHE_SIZE=0 disables the cache,
+	// GOOGLE_BOOKS_CACHE_PERSIST=true also keeps them in the database)
+	if size := envInt("GOOGLE_BOOKS_CACHE_SIZE", service.DefaultCacheSize); size > 0 {
+		opts := service.CacheOptions{
+			Size: size,
+			TTL:  envDuration("GOOGLE_BOOKS_CACHE_TTL", service.DefaultCacheTTL),
+		}
+		if persist, _ := strconv.ParseBool(os.Getenv("GOOGLE_BOOKS_CACHE_PERSIST")); persist {
+			opts.Store = storage.NewCacheRepo(db)
+			if n, err := opts.Store.DeleteExpired(ctx, time.Now()); err != nil {
+				log.Fatal(err)
+			} else if n > 0 {
+				log.Printf("removed %d expired google books cache entries", n)
+			}
+		}
+		googleSvc = service.NewCachedGoogleBooks(googleSvc, opts)
+	}
+	bookSvc := service.NewBookService(bookRepo, wishlistRepo, googleSvc)
+
+	// Searchable catalogues; BOOKS_SEARCH_PROVIDER picks the default
+	// (google_books, open_library or aggregate)
+	openLibrarySvc := service.NewOpenLibraryService(service.OpenLibraryOptions{
+		BaseURL:   os.Getenv("OPEN_LIBRARY_BASE_URL"),
+		CoversURL: os.Getenv("OPEN_LIBRARY_COVERS_URL"),
+		Timeout:   envDuration("OPEN_LIBRARY_TIMEOUT", service.DefaultOpenLibraryTimeout),
+		UserAgent: os.Getenv("GOOGLE_BOOKS_USER_AGENT"),
+	})
+	catalogSvc := service.NewCatalogService(os.Getenv("BOOKS_SEARCH_PROVIDER"),
+		service.CatalogProvider{Name: service.SourceGoogleBooks, Client: googleSvc},
+		service.CatalogProvider{Name: service.SourceOpenLibrary, Client: openLibrarySvc},
+	)
+	if p := os.Getenv("BOOKS_SEARCH_PROVIDER"); p != "" && p != service.ProviderAggregate && !slices.Contains(catalogSvc.Providers(), p) {
+		log.Fatalf("unknown BOOKS_SEARCH_PROVIDER=%q", p)
+	}
+
+	// Fill in missing book metadata in the background (ENRICH_INTERVAL=0 disables
+	// the periodic run; POST /wishlist/{id}/books/enrich still works)
+	enrichInterval := envDuration("ENRICH_INTERVAL", service.DefaultEnrichInterval)
+	enricher := service.NewEnricher(bookRepo, wishlistRepo, googleSvc, service.EnrichmentOptions{
+		Interval:       enrichInterval,
+		BatchSize:      envInt("ENRICH_BATCH_SIZE", service.DefaultEnrichBatchSize),
+		LookupInterval: envDuration("ENRICH_LOOKUP_INTERVAL", service.DefaultEnrichLookupInterval),
+		MinConfidence:  envFloat("ENRICH_MIN_CONFIDENCE", service.DefaultEnrichMinConfidence),
+		JobRetention:   envDuration("ENRICH_JOB_RETENTION", service.DefaultEnrichJobRetention),
+	})
+	if enrichInterval > 0 {
+		go enricher.Run(ctx, func(stats service.EnrichStats, err error) {
+			if err != nil {
+				log.Printf("metadata enrichment run aborted, retrying next interval: %v", err)
+			}
+			if stats.Processed > 0 {
+				log.Printf("metadata enrichment: %d looked up, %d enriched, %d skipped, %d failed",
+					stats.Processed, stats.Enriched, stats.Skipped, stats.Failed)
+			}
+		})
+	}
+
+	// Deleted wishlists and books stay restorable for TRASH_RETENTION, then are
+	// purged every TRASH_PURGE_INTERVAL (0 disables purging; items are kept)
+	purgeInterval := envDuration("TRASH_PURGE_INTERVAL", service.DefaultTrashPurgeInterval)
+	trashSvc := service.NewTrashService(wishlistRepo, bookRepo, service.TrashOptions{
+		Retention:     envDuration("TRASH_RETENTION", service.DefaultTrashRetention),
+		PurgeInterval: purgeInterval,
+	})
+	if purgeInterval > 0 {
+		go trashSvc.Run(ctx, func(stats service.PurgeStats, err error) {
+			if err != nil {
+				log.Printf("trash purge failed: %v", err)
+			}
+			if stats.Wishlists > 0 || stats.Books > 0 {
+				log.Printf("trash purge: %d wishlist(s) and %d book(s) permanently deleted", stats.Wishlists, stats.Books)
+			}
+		})
+	}
+
+	// Initialize JWT manager (signing key, expiry and clock skew from environment)
+	tokens, err := auth.NewManager(auth.Config{
+		SigningKey: jwtSigningKey(),
+		Expiry:     envDuration("JWT_EXPIRY", auth.DefaultExpiry),
+		ClockSkew:  envDuration("JWT_CLOCK_SKEW", auth.DefaultClockSkew),
+		Issuer:     os.Getenv("JWT_ISSUER"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Initialize HTTP handlers
 	mainHandler := handler.NewHTTPHandler(wishlistSvc, userSvc)
+	authHandler := handler.NewAuthHTTP(userSvc, tokens)
 	bookHandler := handler.NewBookHTTP(bookSvc)
-	googleHandler := handler.NewGoogleBooksHTTP(googleSvc)
+	googleHandler := handler.NewGoogleBooksHTTP(googleSvc, catalogSvc)
+	enrichHandler := handler.NewEnrichmentHTTP(enricher)
+	searchHandler := handler.NewWishlistSearchHTTP(service.NewWishlistSearchService(bookRepo))
+	trashHandler := handler.NewTrashHTTP(trashSvc)
 
 	// Create a new router
 	r := mux.NewRouter()
 
-	// API routes (grouped under /api)
+	// API routes (grouped under /api); each request gets a deadline of
+	// REQUEST_TIMEOUT (0 disables it) that cancels its queries and upstream calls
 	api := r.PathPrefix("/api").Subrouter()
+	api.Use(handler.Timeout(envDuration("REQUEST_TIMEOUT", handler.DefaultRequestTimeout)))
+
+	// Public routes (no token required)
+	api.HandleFunc("/users/register", mainHandler.RegisterUser).Methods(http.MethodPost) // Register a new user
+	api.HandleFunc("/users/login", authHandler.Login).Methods(http.MethodPost)           // Obtain a JWT
+	api.HandleFunc("/isbn/{isbn}", bookHandler.CheckISBN).Methods(http.MethodGet)        // Validate and convert an ISBN
+
+	// Google Books and catalogue search routes
+	googleHandler.RegisterGoogleRoutes(api)
+
+	// Protected routes (require "Authorization: Bearer <token>")
+	secured := api.NewRoute().Subrouter()
+	secured.Use(auth.Middleware(tokens))
+
+	// Admin routes (the caller must also be an admin)
+	admin := secured.NewRoute().Subrouter()
+	admin.Use(handler.RequireAdmin(userSvc))
+	admin.HandleFunc("/users", mainHandler.ListUsers).Methods(http.MethodGet)                // List all users
+	admin.HandleFunc("/books/cache/stats", googleHandler.CacheStats).Methods(http.MethodGet) // Google Books cache counters
 
 	// User and Wishlist routes
-	api.HandleFunc("/users/register", mainHandler.RegisterUser).Methods(http.MethodPost)    // Register a new user
-	api.HandleFunc("/users", mainHandler.ListUsers).Methods(http.MethodGet)                 // List all users
-	api.HandleFunc("/wishlist", mainHandler.CreateWishlist).Methods(http.MethodPost)        // Create a new wishlist
-	api.HandleFunc("/wishlist", mainHandler.ListWishlists).Methods(http.MethodGet)          // List all wishlists
-	api.HandleFunc("/wishlist/{id}", mainHandler.DeleteWishlist).Methods(http.MethodDelete) // Delete a wishlist by ID
+	secured.HandleFunc("/users/me", mainHandler.Me).Methods(http.MethodGet)                     // Get the authenticated user
+	secured.HandleFunc("/wishlist", mainHandler.CreateWishlist).Methods(http.MethodPost)        // Create a new wishlist
+	secured.HandleFunc("/wishlist", mainHandler.ListWishlists).Methods(http.MethodGet)          // List all wishlists
+	secured.HandleFunc("/wishlist/{id}", mainHandler.GetWishlist).Methods(http.MethodGet)       // Get a wishlist by ID
+	secured.HandleFunc("/wishlist/{id}", mainHandler.UpdateWishlist).Methods(http.MethodPut)    // Replace (rename) a wishlist
+	secured.HandleFunc("/wishlist/{id}", mainHandler.PatchWishlist).Methods(http.MethodPatch)   // Partially update a wishlist
+	secured.HandleFunc("/wishlist/{id}", mainHandler.DeleteWishlist).Methods(http.MethodDelete) // Move a wishlist to the trash
 
 	// Book routes (within a wishlist)
-	api.HandleFunc("/wishlist/{id}/books", bookHandler.AddBook).Methods(http.MethodPost)               // Add a book to a wishlist
-	api.HandleFunc("/wishlist/{id}/books", bookHandler.ListBooks).Methods(http.MethodGet)              // List books in a wishlist
-	api.HandleFunc("/wishlist/{id}/books/{bookID}", bookHandler.DeleteBook).Methods(http.MethodDelete) // Delete a book from a wishlist
+	secured.HandleFunc("/wishlist/{id}/books", bookHandler.AddBook).Methods(http.MethodPost)                      // Add a book to a wishlist
+	secured.HandleFunc("/wishlist/{id}/books", bookHandler.ListBooks).Methods(http.MethodGet)                     // List books in a wishlist
+	secured.HandleFunc("/wishlist/{id}/books/from-google", bookHandler.AddFromGoogle).Methods(http.MethodPost)    // Import a Google Books volume
+	secured.HandleFunc("/wishlist/{id}/duplicates", bookHandler.ListDuplicates).Methods(http.MethodGet)           // List likely duplicate books
+	secured.HandleFunc("/wishlist/{id}/books/enrich", enrichHandler.EnrichBooks).Methods(http.MethodPost)         // Start a metadata enrichment job
+	secured.HandleFunc("/wishlist/{id}/books/enrich/{jobID}", enrichHandler.GetEnrichJob).Methods(http.MethodGet) // Poll an enrichment job
+	secured.HandleFunc("/wishlist/{id}/books/{bookID}", bookHandler.GetBook).Methods(http.MethodGet)              // Get a book from a wishlist
+	secured.HandleFunc("/wishlist/{id}/books/{bookID}", bookHandler.UpdateBook).Methods(http.MethodPut)           // Replace a book's fields
+	secured.HandleFunc("/wishlist/{id}/books/{bookID}", bookHandler.PatchBook).Methods(http.MethodPatch)          // Partially update a book
+	secured.HandleFunc("/wishlist/{id}/books/{bookID}", bookHandler.DeleteBook).Methods(http.MethodDelete)        // Move a book to the trash
 
-	// Google Books routes (search integration)
-	googleHandler.RegisterGoogleRoutes(api)
+	// Full-text search across the caller's wishlists
+	secured.HandleFunc("/search", searchHandler.Search).Methods(http.MethodGet)
+
+	// Trash: deleted wishlists and books, restorable until they are purged
+	secured.HandleFunc("/trash", trashHandler.ListTrash).Methods(http.MethodGet)                                   // List deleted wishlists and books
+	secured.HandleFunc("/wishlist/{id}/restore", trashHandler.RestoreWishlist).Methods(http.MethodPost)            // Restore a deleted wishlist
+	secured.HandleFunc("/wishlist/{id}/books/{bookID}/restore", trashHandler.RestoreBook).Methods(http.MethodPost) // Restore a deleted book
 
 	// Swagger UI (API documentation)
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
@@ -82,3 +262,62 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// jwtSigningKey loads the JWT signing key from JWT_SECRET.
+// When unset, a random key is generated so tokens only survive until restart.
+func jwtSigningKey() []byte {
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	log.Println("JWT_SECRET not set, using a random signing key (tokens will not survive restarts)")
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatal(err)
+	}
+	return key
+}
+
+// envDuration parses a time.Duration (e.g. "24h", "30s") from the given
+// environment variable, falling back to def when unset or invalid.
+func envDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %s", key, v, def)
+		return def
+	}
+	return d
+}
+
+// envInt parses an integer from the given environment variable,
+// falling back to def when unset or invalid.
+func envInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %d", key, v, def)
+		return def
+	}
+	return n
+}
+
+// envFloat parses a floating-point number from the given environment variable,
+// falling back to def when unset or invalid.
+func envFloat(key string, def float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %g", key, v, def)
+		return def
+	}
+	return f
+}
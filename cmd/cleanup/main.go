@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/storage"
+)
+
+// main is a one-off maintenance command that finds and removes books whose
+// wishlist was deleted before ON DELETE CASCADE was enforced.
+//
+// Usage:
+//
+//	go run ./cmd/cleanup [-db wishlist.db] [-dry-run]
+func main() {
+	// Database path defaults to DB_PATH (or wishlist.db), like the API server
+	defaultPath := os.Getenv("DB_PATH")
+	if defaultPath == "" {
+		defaultPath = "wishlist.db"
+	}
+	dbPath := flag.String("db", defaultPath, "path to the SQLite database")
+	dryRun := flag.Bool("dry-run", false, "only list orphaned books, do not delete them")
+	flag.Parse()
+
+	db, err := storage.InitDB(*dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	orphans, err := storage.FindOrphanBooks(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, b := range orphans {
+		log.Printf("orphan book id=%d wishlist_id=%d title=%q", b.ID, b.WishlistID, b.Title)
+	}
+	if *dryRun || len(orphans) == 0 {
+		log.Printf("found %d orphaned book(s)", len(orphans))
+		return
+	}
+
+	n, err := storage.DeleteOrphanBooks(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("deleted %d orphaned book(s)", n)
+}
@@ -15,198 +15,416 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/books/search": {
+        "/books/cache/stats": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Hit, miss and coalescing counters of the Google Books cache, for monitoring.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "books"
                 ],
-                "summary": "Search books using Google Books API",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Search term",
-                        "name": "q",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
+                "summary": "Google Books cache counters (admin only)",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_deividmendozatech-stack_wishlist_internal_service.GoogleBook"
-                            }
+                            "$ref": "#/definitions/internal_handler.CacheStatsResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error"
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Caching is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/users": {
+        "/books/search": {
             "get": {
+                "description": "At least one of q, title, author, isbn or subject is required. The field\nfilters are sent to Google as intitle:, inauthor:, isbn: and subject: terms.\nprovider=aggregate queries every provider concurrently, merges volumes that\nshare an ISBN and reports the provider of each field in field_sources.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "books"
+                ],
+                "summary": "Search books in Google Books, Open Library or both",
+                "parameters": [
+                    {
+                        "enum": [
+                            "google_books",
+                            "open_library",
+                            "aggregate"
+                        ],
+                        "type": "string",
+                        "description": "Catalogue to search (default: google_books)",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Free-text search terms",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Words in the title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Words in the author name",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISBN-10 or ISBN-13",
+                        "name": "isbn",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject or category",
+                        "name": "subject",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Zero-based index of the first result",
+                        "name": "startIndex",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 40,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "maxResults",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "relevance",
+                            "newest"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "orderBy",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "all",
+                            "books",
+                            "magazines"
+                        ],
+                        "type": "string",
+                        "description": "Restrict by print type",
+                        "name": "printType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Two-letter ISO 639-1 language code",
+                        "name": "language",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List registered users",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_deividmendozatech-stack_wishlist_internal_service.User"
-                            }
+                            "$ref": "#/definitions/internal_handler.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Google Books quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Google Books rejected the request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Google Books unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/register": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/books/volumes/{volumeID}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "books"
                 ],
-                "summary": "Register a new user",
+                "summary": "Get a Google Books volume",
                 "parameters": [
                     {
-                        "description": "User data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.RegisterUserRequest"
-                        }
+                        "type": "string",
+                        "description": "Google Books volume ID",
+                        "name": "volumeID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.VolumeResponse"
+                        }
                     },
                     "400": {
-                        "description": "Bad Request"
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Google Books quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Google Books rejected the request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Google Books unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/wishlist": {
+        "/isbn/{isbn}": {
             "get": {
+                "description": "Strips hyphens and spaces, verifies the check digit and returns both the\nISBN-10 and canonical ISBN-13 forms. Invalid input is reported with valid=false.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "wishlist"
+                    "books"
+                ],
+                "summary": "Validate and convert an ISBN",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ISBN-10 or ISBN-13, hyphens allowed",
+                        "name": "isbn",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List all wishlists for a user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_deividmendozatech-stack_wishlist_internal_service.Wishlist"
-                            }
+                            "$ref": "#/definitions/internal_handler.ISBNResponse"
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Finds books whose title, authors, description or notes contain every word of q,\nas a word or the start of one, ignoring case and accents. Punctuation only\nseparates words. Hits are ranked best first; title and author matches weigh most.\nhighlights holds the matching fields as HTML-escaped text with the matched words\nwrapped in \u003cmark\u003e; description and notes are shortened to the matching passage.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "wishlist"
+                    "search"
                 ],
-                "summary": "Create a new wishlist",
+                "summary": "Search the books in all of the caller's wishlists",
                 "parameters": [
                     {
-                        "description": "Wishlist data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.CreateWishlistRequest"
-                        }
+                        "type": "string",
+                        "description": "Words to find",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of hits, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.SearchHitResponse"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Bad Request"
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/wishlist/{id}": {
-            "delete": {
-                "tags": [
-                    "wishlist"
-                ],
-                "summary": "Delete a wishlist by ID",
-                "parameters": [
+        "/trash": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Wishlist ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Deleted items are kept for a retention period (TRASH_RETENTION, 30 days by default)\nand permanently deleted afterwards; purge_at tells when. Books of a deleted wishlist\nare not listed on their own: restoring the wishlist brings them back.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "List the caller's deleted wishlists and books",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TrashResponse"
+                        }
                     },
-                    "400": {
-                        "description": "Bad Request"
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/wishlist/{id}/books": {
+        "/users": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Results are paged with opaque cursors: when more users follow, the response carries\na Link header with rel=\"next\" and the cursor of the next page in X-Next-Cursor.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "users"
                 ],
-                "summary": "List all books from a wishlist",
+                "summary": "List registered users (admin only)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Wishlist ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page size, 1-200 (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-Next-Cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created (default) or username; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users whose username contains this text",
+                        "name": "username",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -215,12 +433,48 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/github_com_deividmendozatech-stack_wishlist_internal_service.Book"
+                                "$ref": "#/definitions/internal_handler.UserResponse"
                             }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page (rel=next)"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/users/login": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -229,24 +483,110 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "users"
                 ],
-                "summary": "Add a book to the wishlist",
+                "summary": "Log in and obtain a JWT access token",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Wishlist ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
                     {
-                        "description": "Book data",
-                        "name": "data",
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get the authenticated user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UserResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/register": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User data",
+                        "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.AddBookRequest"
+                            "$ref": "#/definitions/internal_handler.RegisterUserRequest"
                         }
                     }
                 ],
@@ -255,163 +595,2214 @@ const docTemplate = `{
                         "description": "Created"
                     },
                     "400": {
-                        "description": "Bad Request"
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Results are paged with opaque cursors: when more wishlists follow, the response carries\na Link header with rel=\"next\" and the cursor of the next page in X-Next-Cursor.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "List all wishlists for a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1-200 (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-Next-Cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created (default) or name; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only wishlists whose name contains this text",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.WishlistResponse"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page (rel=next)"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Create a new wishlist",
+                "parameters": [
+                    {
+                        "description": "Wishlist data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateWishlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.WishlistResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new wishlist"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Get a wishlist by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.WishlistResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Replace a wishlist (rename)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Wishlist data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UpdateWishlistRequest"
+                        }
                     }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.WishlistResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves the wishlist and its books to the trash, from which it can be restored\nuntil it is purged (see GET /trash).",
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Delete a wishlist by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Partially update a wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.PatchWishlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.WishlistResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{id}/books": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Results are paged with opaque cursors: when more books follow, the response carries\na Link header with rel=\"next\" and the cursor of the next page in X-Next-Cursor.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "List all books from a wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1-200 (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-Next-Cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created (default), title or author; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only books whose title contains this text",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only books with an author whose name contains this text",
+                        "name": "author",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.BookResponse"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page (rel=next)"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "If the wishlist already holds the book (same ISBN, or same title and author\nignoring case and accents), on_duplicate decides the outcome: \"reject\" (default)\nreturns 409, \"merge\" fills the stored book's empty fields and returns it with 200,\nand \"allow\" adds the copy anyway.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Add a book to the wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Book data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.AddBookRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "merge",
+                            "allow"
+                        ],
+                        "type": "string",
+                        "default": "reject",
+                        "description": "Duplicate policy",
+                        "name": "on_duplicate",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Merged into an existing book",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BookResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the book"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BookResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the book"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{id}/books/enrich": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts a background job that looks up every book of the wishlist lacking an ISBN\nor a cover. Empty fields are filled in when the match confidence is high enough and\nlisted in auto_filled. Poll the URL in the Location header for progress; if a job\nfor the wishlist is already running, that job is returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Fill in missing book metadata from Google Books",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.EnrichJobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{id}/books/enrich/{jobID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Finished jobs can be polled for an hour (ENRICH_JOB_RETENTION).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Get the progress of a metadata enrichment job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.EnrichJobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{id}/books/from-google": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetches the volume from Google Books and stores it with its full metadata and\nvolume ID. Duplicates are handled as in AddBook (see on_duplicate).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Add a book from a Google Books volume",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Google Books volume",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.AddFromGoogleRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "reject",
+                            "merge",
+                            "allow"
+                        ],
+                        "type": "string",
+                        "default": "reject",
+                        "description": "Duplicate policy",
+                        "name": "on_duplicate",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Merged into an existing book",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BookResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the book"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BookResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the book"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Wishlist or volume not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Google Books quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Google Books rejected the request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Google Books unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{id}/books/{bookID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Get a book from a wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Book ID",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Replace a book's editable fields",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Book ID",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Book data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UpdateBookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves the book to the trash, from which it can be restored until it is purged\n(see GET /trash).",
+                "tags": [
+                    "books"
+                ],
+                "summary": "Remove a book from a wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Book ID",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Partially update a book",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Book ID",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.PatchBookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{id}/books/{bookID}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Takes the book out of the trash, back into its wishlist. A book of a deleted\nwishlist comes back by restoring the wishlist instead.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted book",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Book ID",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{id}/duplicates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Groups books that share an ISBN, or (when either has no ISBN) the same title\nand author ignoring case, accents and punctuation.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "List likely duplicate books in a wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.DuplicateGroupResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlist/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Takes the wishlist out of the trash together with the books it held when it was\ndeleted. Books deleted on their own before that stay in the trash.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.WishlistResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "internal_handler.AddBookRequest": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "Antoine de Saint-Exupéry"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Antoine de Saint-Exupéry"
+                    ]
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Juvenile Fiction"
+                    ]
+                },
+                "description": {
+                    "type": "string",
+                    "example": "A pilot stranded in the desert meets a young prince."
+                },
+                "isbn_10": {
+                    "type": "string",
+                    "example": "0156012197"
+                },
+                "isbn_13": {
+                    "type": "string",
+                    "example": "9780156012195"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Gift idea for Ana"
+                },
+                "page_count": {
+                    "type": "integer",
+                    "example": 96
+                },
+                "published_date": {
+                    "type": "string",
+                    "example": "2000-06-29"
+                },
+                "publisher": {
+                    "type": "string",
+                    "example": "Harcourt"
+                },
+                "thumbnail_url": {
+                    "type": "string",
+                    "example": "https://books.google.com/books/content?id=abc\u0026printsec=frontcover\u0026img=1"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The Little Prince"
+                }
+            }
+        },
+        "internal_handler.AddFromGoogleRequest": {
+            "type": "object",
+            "properties": {
+                "volume_id": {
+                    "type": "string",
+                    "example": "zyTCAlFPjgYC"
+                }
+            }
+        },
+        "internal_handler.BookResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "Antoine de Saint-Exupéry"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Antoine de Saint-Exupéry"
+                    ]
+                },
+                "auto_filled": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "isbn_13",
+                        "thumbnail_url"
+                    ]
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Juvenile Fiction"
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "A pilot stranded in the desert meets a young prince."
+                },
+                "enriched_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "enrichment_confidence": {
+                    "type": "number",
+                    "example": 0.94
+                },
+                "external_id": {
+                    "type": "string",
+                    "example": "zyTCAlFPjgYC"
+                },
+                "external_source": {
+                    "type": "string",
+                    "example": "google_books"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "isbn_10": {
+                    "type": "string",
+                    "example": "0156012197"
+                },
+                "isbn_13": {
+                    "type": "string",
+                    "example": "9780156012195"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Gift idea for Ana"
+                },
+                "page_count": {
+                    "type": "integer",
+                    "example": 96
+                },
+                "published_date": {
+                    "type": "string",
+                    "example": "2000-06-29"
+                },
+                "publisher": {
+                    "type": "string",
+                    "example": "Harcourt"
+                },
+                "thumbnail_url": {
+                    "type": "string",
+                    "example": "https://books.google.com/books/content?id=abc\u0026printsec=frontcover\u0026img=1"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The Little Prince"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-02T00:00:00Z"
+                },
+                "wishlist_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_handler.CacheStatsResponse": {
+            "type": "object",
+            "properties": {
+                "coalesced": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "entries": {
+                    "type": "integer",
+                    "example": 41
+                },
+                "evictions": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "hits": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "misses": {
+                    "type": "integer",
+                    "example": 37
+                },
+                "persistent_hits": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "internal_handler.CreateWishlistRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "My book list"
+                }
+            }
+        },
+        "internal_handler.DuplicateGroupResponse": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.BookResponse"
+                    }
+                },
+                "matched_by": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "isbn"
+                    ]
+                }
+            }
+        },
+        "internal_handler.EnrichJobResponse": {
+            "type": "object",
+            "properties": {
+                "enriched": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "error": {
+                    "type": "string",
+                    "example": "google books quota exceeded, try again later"
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:12Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "9f86d081884c7d65"
+                },
+                "processed": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "running",
+                        "done",
+                        "failed"
+                    ],
+                    "example": "running"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "wishlist_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "wishlist not found"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                }
+            }
+        },
+        "internal_handler.ISBNResponse": {
+            "type": "object",
+            "properties": {
+                "input": {
+                    "type": "string",
+                    "example": "0-15-601219-7"
+                },
+                "isbn_10": {
+                    "type": "string",
+                    "example": "0156012197"
+                },
+                "isbn_13": {
+                    "type": "string",
+                    "example": "9780156012195"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "invalid ISBN-10 \"0-15-601219-8\""
+                },
+                "valid": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handler.IndustryIdentifierResponse": {
+            "type": "object",
+            "properties": {
+                "identifier": {
+                    "type": "string",
+                    "example": "9780134190440"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "ISBN_13"
+                }
+            }
+        },
+        "internal_handler.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "1234"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "david"
                 }
             }
         },
-        "/wishlist/{id}/books/{bookID}": {
-            "delete": {
-                "tags": [
-                    "books"
-                ],
-                "summary": "Remove a book from a wishlist",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Wishlist ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+        "internal_handler.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "internal_handler.PatchBookRequest": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "Antoine de Saint-Exupéry"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     },
-                    {
-                        "type": "integer",
-                        "description": "Book ID",
-                        "name": "bookID",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "example": [
+                        "Antoine de Saint-Exupéry"
+                    ]
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     },
-                    "400": {
-                        "description": "Bad Request"
+                    "example": [
+                        "Juvenile Fiction"
+                    ]
+                },
+                "description": {
+                    "type": "string",
+                    "example": "A pilot stranded in the desert meets a young prince."
+                },
+                "isbn_10": {
+                    "type": "string",
+                    "example": "0156012197"
+                },
+                "isbn_13": {
+                    "type": "string",
+                    "example": "9780156012195"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Gift idea for Ana"
+                },
+                "page_count": {
+                    "type": "integer",
+                    "example": 96
+                },
+                "published_date": {
+                    "type": "string",
+                    "example": "2000-06-29"
+                },
+                "publisher": {
+                    "type": "string",
+                    "example": "Harcourt"
+                },
+                "thumbnail_url": {
+                    "type": "string",
+                    "example": "https://books.google.com/books/content?id=abc\u0026printsec=frontcover\u0026img=1"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The Little Prince"
+                }
+            }
+        },
+        "internal_handler.PatchWishlistRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Read next"
+                }
+            }
+        },
+        "internal_handler.RegisterUserRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "1234"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "david"
+                }
+            }
+        },
+        "internal_handler.SearchHitResponse": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "$ref": "#/definitions/internal_handler.BookResponse"
+                },
+                "highlights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
                     },
-                    "500": {
-                        "description": "Internal Server Error"
+                    "example": {
+                        "authors": "J. R. R. \u003cmark\u003eTolkien\u003c/mark\u003e"
                     }
+                },
+                "score": {
+                    "type": "number",
+                    "example": 14.2
+                },
+                "wishlist": {
+                    "$ref": "#/definitions/internal_handler.WishlistResponse"
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_deividmendozatech-stack_wishlist_internal_service.Book": {
+        },
+        "internal_handler.SearchResponse": {
             "type": "object",
             "properties": {
-                "author": {
-                    "description": "Book author",
-                    "type": "string"
+                "has_more": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "id": {
-                    "description": "Auto-increment primary key",
-                    "type": "integer"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.VolumeResponse"
+                    }
                 },
-                "title": {
-                    "description": "Book title",
-                    "type": "string"
+                "max_results": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "next": {
+                    "type": "string",
+                    "example": "/api/books/search?q=golang\u0026startIndex=10"
+                },
+                "next_start_index": {
+                    "type": "integer",
+                    "example": 10
                 },
-                "wishlistID": {
-                    "description": "Reference to the parent wishlist",
-                    "type": "integer"
+                "provider": {
+                    "type": "string",
+                    "example": "google_books"
+                },
+                "provider_errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "start_index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total_items": {
+                    "type": "integer",
+                    "example": 523
                 }
             }
         },
-        "github_com_deividmendozatech-stack_wishlist_internal_service.GoogleBook": {
+        "internal_handler.TrashResponse": {
             "type": "object",
             "properties": {
-                "author": {
-                    "type": "string"
+                "books": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.TrashedBookResponse"
+                    }
                 },
-                "title": {
-                    "type": "string"
+                "wishlists": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.TrashedWishlistResponse"
+                    }
                 }
             }
         },
-        "github_com_deividmendozatech-stack_wishlist_internal_service.User": {
+        "internal_handler.TrashedBookResponse": {
             "type": "object",
             "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "Antoine de Saint-Exupéry"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Antoine de Saint-Exupéry"
+                    ]
+                },
+                "auto_filled": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "isbn_13",
+                        "thumbnail_url"
+                    ]
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Juvenile Fiction"
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "example": "2025-01-03T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "A pilot stranded in the desert meets a young prince."
+                },
+                "enriched_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "enrichment_confidence": {
+                    "type": "number",
+                    "example": 0.94
+                },
+                "external_id": {
+                    "type": "string",
+                    "example": "zyTCAlFPjgYC"
+                },
+                "external_source": {
+                    "type": "string",
+                    "example": "google_books"
+                },
                 "id": {
-                    "description": "Auto-increment primary key",
-                    "type": "integer"
+                    "type": "integer",
+                    "example": 1
                 },
-                "password": {
-                    "description": "Hashed password",
-                    "type": "string"
+                "isbn_10": {
+                    "type": "string",
+                    "example": "0156012197"
                 },
-                "username": {
-                    "description": "Unique username",
-                    "type": "string"
+                "isbn_13": {
+                    "type": "string",
+                    "example": "9780156012195"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Gift idea for Ana"
+                },
+                "page_count": {
+                    "type": "integer",
+                    "example": 96
+                },
+                "published_date": {
+                    "type": "string",
+                    "example": "2000-06-29"
+                },
+                "publisher": {
+                    "type": "string",
+                    "example": "Harcourt"
+                },
+                "purge_at": {
+                    "type": "string",
+                    "example": "2025-02-02T00:00:00Z"
+                },
+                "thumbnail_url": {
+                    "type": "string",
+                    "example": "https://books.google.com/books/content?id=abc\u0026printsec=frontcover\u0026img=1"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The Little Prince"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-02T00:00:00Z"
+                },
+                "wishlist_id": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "github_com_deividmendozatech-stack_wishlist_internal_service.Wishlist": {
+        "internal_handler.TrashedWishlistResponse": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "example": "2025-01-03T00:00:00Z"
+                },
                 "id": {
-                    "description": "Auto-increment primary key",
-                    "type": "integer"
+                    "type": "integer",
+                    "example": 1
                 },
                 "name": {
-                    "description": "Name of the wishlist",
-                    "type": "string"
+                    "type": "string",
+                    "example": "Pending Books"
+                },
+                "purge_at": {
+                    "type": "string",
+                    "example": "2025-02-02T00:00:00Z"
                 },
-                "userID": {
-                    "description": "Reference to the owning user",
-                    "type": "integer"
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-02T00:00:00Z"
                 }
             }
         },
-        "internal_handler.AddBookRequest": {
+        "internal_handler.UpdateBookRequest": {
             "type": "object",
             "properties": {
                 "author": {
                     "type": "string",
                     "example": "Antoine de Saint-Exupéry"
                 },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Antoine de Saint-Exupéry"
+                    ]
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Juvenile Fiction"
+                    ]
+                },
+                "description": {
+                    "type": "string",
+                    "example": "A pilot stranded in the desert meets a young prince."
+                },
+                "isbn_10": {
+                    "type": "string",
+                    "example": "0156012197"
+                },
+                "isbn_13": {
+                    "type": "string",
+                    "example": "9780156012195"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Gift idea for Ana"
+                },
+                "page_count": {
+                    "type": "integer",
+                    "example": 96
+                },
+                "published_date": {
+                    "type": "string",
+                    "example": "2000-06-29"
+                },
+                "publisher": {
+                    "type": "string",
+                    "example": "Harcourt"
+                },
+                "thumbnail_url": {
+                    "type": "string",
+                    "example": "https://books.google.com/books/content?id=abc\u0026printsec=frontcover\u0026img=1"
+                },
                 "title": {
                     "type": "string",
                     "example": "The Little Prince"
                 }
             }
         },
-        "internal_handler.CreateWishlistRequest": {
+        "internal_handler.UpdateWishlistRequest": {
             "type": "object",
             "properties": {
                 "name": {
                     "type": "string",
-                    "example": "My book list"
+                    "example": "Read next"
                 }
             }
         },
-        "internal_handler.RegisterUserRequest": {
+        "internal_handler.UserResponse": {
             "type": "object",
             "properties": {
-                "password": {
+                "created_at": {
                     "type": "string",
-                    "example": "1234"
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_admin": {
+                    "type": "boolean",
+                    "example": false
                 },
                 "username": {
                     "type": "string",
                     "example": "david"
                 }
             }
+        },
+        "internal_handler.VolumeResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "Alan A. A. Donovan"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Alan A. A. Donovan",
+                        "Brian W. Kernighan"
+                    ]
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Computers"
+                    ]
+                },
+                "description": {
+                    "type": "string",
+                    "example": "The authoritative resource to writing clear and idiomatic Go."
+                },
+                "field_sources": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "page_count": "open_library",
+                        "title": "google_books"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "zyTCAlFPjgYC"
+                },
+                "industry_identifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.IndustryIdentifierResponse"
+                    }
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "page_count": {
+                    "type": "integer",
+                    "example": 380
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "google_books"
+                },
+                "published_date": {
+                    "type": "string",
+                    "example": "2015-11-16"
+                },
+                "publisher": {
+                    "type": "string",
+                    "example": "Addison-Wesley Professional"
+                },
+                "thumbnail_url": {
+                    "type": "string",
+                    "example": "https://books.google.com/books/content?id=zyTCAlFPjgYC\u0026printsec=frontcover\u0026img=1\u0026zoom=1"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "The Go Programming Language"
+                }
+            }
+        },
+        "internal_handler.WishlistResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Pending Books"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-02T00:00:00Z"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Type \"Bearer\" followed by a space and the JWT returned by /users/login.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
-	Version:          "",
+	Version:          "1.0",
 	Host:             "",
-	BasePath:         "",
+	BasePath:         "/api",
 	Schemes:          []string{},
-	Title:            "",
-	Description:      "",
+	Title:            "Wishlist API",
+	Description:      "REST API to manage book wishlists.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
@@ -0,0 +1,407 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+)
+
+//
+// ───────────────────────── RESPONSE DTOs ─────────────────────────
+//
+// Domain models from internal/service are never encoded directly; handlers
+// convert them into these public representations first so that internal
+// fields (e.g. password hashes) cannot leak to clients.
+//
+
+// UserResponse is the public representation of a user.
+type UserResponse struct {
+	ID       uint   `json:"id"       example:"1"`
+	Username string `json:"username" example:"david"`
+	IsAdmin  bool   `json:"is_admin" example:"false"`
+
+	CreatedAt *time.Time `json:"created_at,omitempty" example:"2025-01-01T00:00:00Z"`
+}
+
+// WishlistResponse is the public representation of a wishlist.
+// Timestamps are omitted for rows created before they were recorded.
+type WishlistResponse struct {
+	ID   uint   `json:"id"   example:"1"`
+	Name string `json:"name" example:"Pending Books"`
+
+	CreatedAt *time.Time `json:"created_at,omitempty" example:"2025-01-01T00:00:00Z"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" example:"2025-01-02T00:00:00Z"`
+}
+
+// BookResponse is the public representation of a book inside a wishlist.
+// Author repeats the first entry of Authors for older clients. Timestamps
+// are omitted for rows created before they were recorded.
+type BookResponse struct {
+	ID            uint     `json:"id"             example:"1"`
+	WishlistID    uint     `json:"wishlist_id"    example:"1"`
+	Title         string   `json:"title"          example:"The Little Prince"`
+	Author        string   `json:"author"         example:"Antoine de Saint-Exupéry"`
+	Authors       []string `json:"authors"        example:"Antoine de Saint-Exupéry"`
+	ISBN10        string   `json:"isbn_10"        example:"0156012197"`
+	ISBN13        string   `json:"isbn_13"        example:"9780156012195"`
+	Publisher     string   `json:"publisher"      example:"Harcourt"`
+	PublishedDate string   `json:"published_date" example:"2000-06-29"`
+	PageCount     int      `json:"page_count"     example:"96"`
+	Language      string   `json:"language"       example:"en"`
+	Categories    []string `json:"categories"     example:"Juvenile Fiction"`
+	Description   string   `json:"description"    example:"A pilot stranded in the desert meets a young prince."`
+	ThumbnailURL  string   `json:"thumbnail_url"  example:"https://books.google.com/books/content?id=abc&printsec=frontcover&img=1"`
+	Notes         string   `json:"notes"          example:"Gift idea for Ana"`
+
+	ExternalSource string `json:"external_source,omitempty" example:"google_books"`
+	ExternalID     string `json:"external_id,omitempty"     example:"zyTCAlFPjgYC"`
+
+	EnrichedAt           *time.Time `json:"enriched_at,omitempty"           example:"2025-01-01T00:00:00Z"`
+	EnrichmentConfidence float64    `json:"enrichment_confidence,omitempty" example:"0.94"`
+	AutoFilled           []string   `json:"auto_filled,omitempty"           example:"isbn_13,thumbnail_url"`
+
+	CreatedAt *time.Time `json:"created_at,omitempty" example:"2025-01-01T00:00:00Z"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" example:"2025-01-02T00:00:00Z"`
+}
+
+// SearchHitResponse is a book found by a search across the caller's wishlists.
+// Highlights maps the matching fields ("title", "authors", "description",
+// "notes") to HTML-escaped text with the matched words wrapped in <mark>.
+type SearchHitResponse struct {
+	Book       BookResponse      `json:"book"`
+	Wishlist   WishlistResponse  `json:"wishlist"`
+	Score      float64           `json:"score"      example:"14.2"`
+	Highlights map[string]string `json:"highlights" example:"authors:J. R. R. <mark>Tolkien</mark>"`
+}
+
+// TrashResponse lists the caller's deleted wishlists and books, most recently
+// deleted first. Books of a deleted wishlist are not listed on their own:
+// restoring the wishlist brings them back.
+type TrashResponse struct {
+	Wishlists []TrashedWishlistResponse `json:"wishlists"`
+	Books     []TrashedBookResponse     `json:"books"`
+}
+
+// TrashedWishlistResponse is a deleted wishlist and when it will be purged.
+type TrashedWishlistResponse struct {
+	WishlistResponse
+	DeletedAt time.Time `json:"deleted_at" example:"2025-01-03T00:00:00Z"`
+	PurgeAt   time.Time `json:"purge_at"   example:"2025-02-02T00:00:00Z"`
+}
+
+// TrashedBookResponse is a deleted book and when it will be purged.
+type TrashedBookResponse struct {
+	BookResponse
+	DeletedAt time.Time `json:"deleted_at" example:"2025-01-03T00:00:00Z"`
+	PurgeAt   time.Time `json:"purge_at"   example:"2025-02-02T00:00:00Z"`
+}
+
+// DuplicateGroupResponse is a set of books that look like the same book.
+type DuplicateGroupResponse struct {
+	MatchedBy []string       `json:"matched_by" example:"isbn"`
+	Books     []BookResponse `json:"books"`
+}
+
+// SearchResponse is one page of catalogue search results.
+// Next is the URL of the following page and is omitted on the last page.
+// ProviderErrors lists the providers that failed during an aggregate search.
+type SearchResponse struct {
+	Provider       string            `json:"provider"                   example:"google_books"`
+	TotalItems     int               `json:"total_items"                example:"523"`
+	StartIndex     int               `json:"start_index"                example:"0"`
+	MaxResults     int               `json:"max_results"                example:"10"`
+	Items          []VolumeResponse  `json:"items"`
+	HasMore        bool              `json:"has_more"                   example:"true"`
+	NextStartIndex *int              `json:"next_start_index,omitempty" example:"10"`
+	Next           string            `json:"next,omitempty"             example:"/api/books/search?q=golang&startIndex=10"`
+	ProviderErrors map[string]string `json:"provider_errors,omitempty"`
+}
+
+// VolumeResponse is the public representation of a catalogue volume.
+// Provider and FieldSources are only set by catalogue searches; FieldSources
+// maps each populated field, by JSON name, to the provider it came from.
+type VolumeResponse struct {
+	ID                  string                       `json:"id"                        example:"zyTCAlFPjgYC"`
+	Title               string                       `json:"title"                     example:"The Go Programming Language"`
+	Author              string                       `json:"author"                    example:"Alan A. A. Donovan"`
+	Authors             []string                     `json:"authors"                   example:"Alan A. A. Donovan,Brian W. Kernighan"`
+	Publisher           string                       `json:"publisher,omitempty"       example:"Addison-Wesley Professional"`
+	PublishedDate       string                       `json:"published_date,omitempty"  example:"2015-11-16"`
+	Description         string                       `json:"description,omitempty"     example:"The authoritative resource to writing clear and idiomatic Go."`
+	PageCount           int                          `json:"page_count,omitempty"      example:"380"`
+	Language            string                       `json:"language,omitempty"        example:"en"`
+	Categories          []string                     `json:"categories,omitempty"      example:"Computers"`
+	IndustryIdentifiers []IndustryIdentifierResponse `json:"industry_identifiers"`
+	ThumbnailURL        string                       `json:"thumbnail_url,omitempty"   example:"https://books.google.com/books/content?id=zyTCAlFPjgYC&printsec=frontcover&img=1&zoom=1"`
+
+	Provider     string            `json:"provider,omitempty"      example:"google_books"`
+	FieldSources map[string]string `json:"field_sources,omitempty" example:"title:google_books,page_count:open_library"`
+}
+
+// IndustryIdentifierResponse is a standard identifier of a volume, such as an ISBN.
+type IndustryIdentifierResponse struct {
+	Type       string `json:"type"       example:"ISBN_13"`
+	Identifier string `json:"identifier" example:"9780134190440"`
+}
+
+// CacheStatsResponse reports the Google Books cache counters.
+type CacheStatsResponse struct {
+	Hits           uint64 `json:"hits"            example:"120"`
+	PersistentHits uint64 `json:"persistent_hits" example:"4"`
+	Misses         uint64 `json:"misses"          example:"37"`
+	Coalesced      uint64 `json:"coalesced"       example:"6"`
+	Evictions      uint64 `json:"evictions"       example:"0"`
+	Entries        int    `json:"entries"         example:"41"`
+}
+
+// EnrichJobResponse reports the progress of a metadata enrichment job.
+type EnrichJobResponse struct {
+	ID         string     `json:"id"                    example:"9f86d081884c7d65"`
+	WishlistID uint       `json:"wishlist_id"           example:"1"`
+	Status     string     `json:"status"                example:"running" enums:"running,done,failed"`
+	Total      int        `json:"total"                 example:"12"`
+	Processed  int        `json:"processed"             example:"5"`
+	Enriched   int        `json:"enriched"              example:"4"`
+	Skipped    int        `json:"skipped"               example:"1"`
+	Failed     int        `json:"failed"                example:"0"`
+	Error      string     `json:"error,omitempty"       example:"google books quota exceeded, try again later"`
+	StartedAt  time.Time  `json:"started_at"            example:"2025-01-01T00:00:00Z"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" example:"2025-01-01T00:00:12Z"`
+}
+
+// ISBNResponse reports whether an ISBN is valid together with both normalized forms.
+type ISBNResponse struct {
+	Input  string `json:"input"            example:"0-15-601219-7"`
+	Valid  bool   `json:"valid"            example:"true"`
+	ISBN10 string `json:"isbn_10"          example:"0156012197"`
+	ISBN13 string `json:"isbn_13"          example:"9780156012195"`
+	Reason string `json:"reason,omitempty" example:"invalid ISBN-10 \"0-15-601219-8\""`
+}
+
+//
+// ───────────────────────── MAPPERS ─────────────────────────
+//
+
+// toUserResponse maps a domain user to its public representation.
+func toUserResponse(u service.User) UserResponse {
+	return UserResponse{ID: u.ID, Username: u.Username, IsAdmin: u.IsAdmin, CreatedAt: optionalTime(u.CreatedAt)}
+}
+
+// toUserResponses maps a slice of domain users, never returning nil.
+func toUserResponses(users []service.User) []UserResponse {
+	out := make([]UserResponse, 0, len(users))
+	for _, u := range users {
+		out = append(out, toUserResponse(u))
+	}
+	return out
+}
+
+// toWishlistResponse maps a domain wishlist to its public representation.
+func toWishlistResponse(w service.Wishlist) WishlistResponse {
+	return WishlistResponse{ID: w.ID, Name: w.Name, CreatedAt: optionalTime(w.CreatedAt), UpdatedAt: optionalTime(w.UpdatedAt)}
+}
+
+// toWishlistResponses maps a slice of domain wishlists, never returning nil.
+func toWishlistResponses(lists []service.Wishlist) []WishlistResponse {
+	out := make([]WishlistResponse, 0, len(lists))
+	for _, w := range lists {
+		out = append(out, toWishlistResponse(w))
+	}
+	return out
+}
+
+// toBookResponse maps a domain book to its public representation.
+// Lists are never nil so that clients always receive JSON arrays.
+func toBookResponse(b service.Book) BookResponse {
+	return BookResponse{
+		ID:            b.ID,
+		WishlistID:    b.WishlistID,
+		Title:         b.Title,
+		Author:        b.Author,
+		Authors:       nonNil(b.Authors),
+		ISBN10:        b.ISBN10,
+		ISBN13:        b.ISBN13,
+		Publisher:     b.Publisher,
+		PublishedDate: b.PublishedDate,
+		PageCount:     b.PageCount,
+		Language:      b.Language,
+		Categories:    nonNil(b.Categories),
+		Description:   b.Description,
+		ThumbnailURL:  b.ThumbnailURL,
+		Notes:         b.Notes,
+
+		ExternalSource: b.ExternalSource,
+		ExternalID:     b.ExternalID,
+
+		EnrichedAt:           b.EnrichedAt,
+		EnrichmentConfidence: b.EnrichmentConfidence,
+		AutoFilled:           b.AutoFilled,
+
+		CreatedAt: optionalTime(b.CreatedAt),
+		UpdatedAt: optionalTime(b.UpdatedAt),
+	}
+}
+
+// toBookResponses maps a slice of domain books, never returning nil.
+func toBookResponses(books []service.Book) []BookResponse {
+	out := make([]BookResponse, 0, len(books))
+	for _, b := range books {
+		out = append(out, toBookResponse(b))
+	}
+	return out
+}
+
+// toTrashResponse maps a user's trash, never returning nil lists. Items are
+// purged Retention after they were deleted.
+func toTrashResponse(t *service.Trash) TrashResponse {
+	out := TrashResponse{
+		Wishlists: make([]TrashedWishlistResponse, 0, len(t.Wishlists)),
+		Books:     make([]TrashedBookResponse, 0, len(t.Books)),
+	}
+	for _, w := range t.Wishlists {
+		out.Wishlists = append(out.Wishlists, TrashedWishlistResponse{
+			WishlistResponse: toWishlistResponse(w),
+			DeletedAt:        w.DeletedAt.Time,
+			PurgeAt:          w.DeletedAt.Time.Add(t.Retention),
+		})
+	}
+	for _, b := range t.Books {
+		out.Books = append(out.Books, TrashedBookResponse{
+			BookResponse: toBookResponse(b),
+			DeletedAt:    b.DeletedAt.Time,
+			PurgeAt:      b.DeletedAt.Time.Add(t.Retention),
+		})
+	}
+	return out
+}
+
+// toDuplicateGroupResponses maps duplicate groups, never returning nil.
+func toDuplicateGroupResponses(groups []service.DuplicateGroup) []DuplicateGroupResponse {
+	out := make([]DuplicateGroupResponse, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, DuplicateGroupResponse{MatchedBy: nonNil(g.MatchedBy), Books: toBookResponses(g.Books)})
+	}
+	return out
+}
+
+// toSearchHitResponses maps wishlist search hits, never returning nil.
+func toSearchHitResponses(hits []service.BookHit) []SearchHitResponse {
+	out := make([]SearchHitResponse, 0, len(hits))
+	for _, h := range hits {
+		out = append(out, SearchHitResponse{
+			Book:       toBookResponse(h.Book),
+			Wishlist:   toWishlistResponse(h.Wishlist),
+			Score:      h.Score,
+			Highlights: h.Highlights,
+		})
+	}
+	return out
+}
+
+// toSearchResponse wraps a search result page. The next-page URL repeats
+// the request's query parameters with startIndex advanced.
+func toSearchResponse(res *service.SearchResult, reqURL *url.URL) SearchResponse {
+	out := SearchResponse{
+		Provider:       res.Provider,
+		TotalItems:     res.TotalItems,
+		StartIndex:     res.StartIndex,
+		MaxResults:     res.MaxResults,
+		Items:          toVolumeResponses(res.Items),
+		HasMore:        res.HasMore,
+		ProviderErrors: res.ProviderErrors,
+	}
+	if res.HasMore {
+		next := res.NextStartIndex
+		q := reqURL.Query()
+		q.Set("startIndex", strconv.Itoa(next))
+		out.NextStartIndex = &next
+		out.Next = reqURL.Path + "?" + q.Encode()
+	}
+	return out
+}
+
+// toVolumeResponse maps a catalogue volume to its public representation.
+// Authors and identifiers are never nil so that clients always receive JSON arrays.
+func toVolumeResponse(v service.GoogleBook) VolumeResponse {
+	ids := make([]IndustryIdentifierResponse, 0, len(v.IndustryIdentifiers))
+	for _, id := range v.IndustryIdentifiers {
+		ids = append(ids, IndustryIdentifierResponse{Type: id.Type, Identifier: id.Identifier})
+	}
+	return VolumeResponse{
+		ID:                  v.ID,
+		Title:               v.Title,
+		Author:              v.Author,
+		Authors:             nonNil(v.Authors),
+		Publisher:           v.Publisher,
+		PublishedDate:       v.PublishedDate,
+		Description:         v.Description,
+		PageCount:           v.PageCount,
+		Language:            v.Language,
+		Categories:          v.Categories,
+		IndustryIdentifiers: ids,
+		ThumbnailURL:        v.ThumbnailURL,
+		Provider:            v.Provider,
+		FieldSources:        v.FieldSources,
+	}
+}
+
+// toVolumeResponses maps a slice of catalogue volumes, never returning nil.
+func toVolumeResponses(volumes []service.GoogleBook) []VolumeResponse {
+	out := make([]VolumeResponse, 0, len(volumes))
+	for _, v := range volumes {
+		out = append(out, toVolumeResponse(v))
+	}
+	return out
+}
+
+// toCacheStatsResponse maps the Google Books cache counters.
+func toCacheStatsResponse(s service.CacheStats) CacheStatsResponse {
+	return CacheStatsResponse{
+		Hits:           s.Hits,
+		PersistentHits: s.PersistentHits,
+		Misses:         s.Misses,
+		Coalesced:      s.Coalesced,
+		Evictions:      s.Evictions,
+		Entries:        s.Entries,
+	}
+}
+
+// toEnrichJobResponse maps an enrichment job to its public representation.
+func toEnrichJobResponse(j *service.EnrichJob) EnrichJobResponse {
+	return EnrichJobResponse{
+		ID:         j.ID,
+		WishlistID: j.WishlistID,
+		Status:     j.Status,
+		Total:      j.Total,
+		Processed:  j.Processed,
+		Enriched:   j.Enriched,
+		Skipped:    j.Skipped,
+		Failed:     j.Failed,
+		Error:      j.Error,
+		StartedAt:  j.StartedAt,
+		FinishedAt: j.FinishedAt,
+	}
+}
+
+// toISBNResponse maps an ISBN check to its public representation.
+func toISBNResponse(c service.ISBNCheck) ISBNResponse {
+	return ISBNResponse{Input: c.Input, Valid: c.Valid, ISBN10: c.ISBN10, ISBN13: c.ISBN13, Reason: c.Reason}
+}
+
+// optionalTime returns a pointer to t, or nil if t is the zero time.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// nonNil returns s, or an empty slice if s is nil.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+)
+
+//
+// ───────────────────────── ERROR RESPONSES ─────────────────────────
+//
+
+// ErrorResponse is the JSON body returned for every failed request.
+type ErrorResponse struct {
+	Error  string `json:"error"  example:"wishlist not found"`
+	Status int    `json:"status" example:"404"`
+}
+
+// writeJSON encodes v as JSON with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an ErrorResponse with the given status and message.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, ErrorResponse{Error: msg, Status: status})
+}
+
+// statusClientClosedRequest is the non-standard status (borrowed from nginx)
+// recorded when the client goes away before the response is ready.
+const statusClientClosedRequest = 499
+
+// writeServiceError maps a service error to its HTTP status code and writes it.
+// Unclassified errors are logged and reported as a generic 500 so that
+// internal details (SQL, upstream URLs) never reach the client. Upstream
+// failures keep their message but are logged together with their cause.
+func writeServiceError(w http.ResponseWriter, err error) {
+	status := statusFor(err)
+	switch status {
+	case http.StatusGatewayTimeout:
+		writeError(w, status, "request timed out")
+		return
+	case statusClientClosedRequest:
+		writeError(w, status, "request cancelled")
+		return
+	}
+	if status == http.StatusInternalServerError {
+		log.Printf("internal error: %v", err)
+		writeError(w, status, "internal server error")
+		return
+	}
+	var se *service.Error
+	if status >= http.StatusInternalServerError && errors.As(err, &se) && se.Err != nil {
+		log.Printf("upstream error: %v: %v", err, se.Err)
+	}
+	writeError(w, status, err.Error())
+}
+
+// statusFor returns the HTTP status code for a service error kind.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrInvalidCredentials):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrUpstreamQuota):
+		return http.StatusTooManyRequests
+	case errors.Is(err, service.ErrUpstreamBadRequest):
+		return http.StatusBadGateway
+	case errors.Is(err, service.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
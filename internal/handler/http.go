@@ -2,10 +2,15 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/deividmendozatech-stack/wishlist/pkg/auth"
 	"github.com/gorilla/mux"
 )
 
@@ -19,6 +24,19 @@ type CreateWishlistRequest struct {
 	Name string `json:"name" example:"My book list"`
 }
 
+// UpdateWishlistRequest represents the payload to replace a wishlist (PUT).
+// Used in Swagger documentation.
+type UpdateWishlistRequest struct {
+	Name string `json:"name" example:"Read next"`
+}
+
+// PatchWishlistRequest represents a partial wishlist update (PATCH).
+// Omitted fields are left unchanged.
+// Used in Swagger documentation.
+type PatchWishlistRequest struct {
+	Name *string `json:"name,omitempty" example:"Read next"`
+}
+
 // RegisterUserRequest represents the payload to register a new user.
 // Used in Swagger documentation.
 type RegisterUserRequest struct {
@@ -26,11 +44,136 @@ type RegisterUserRequest struct {
 	Password string `json:"password" example:"1234"`
 }
 
+// LoginRequest represents the payload to obtain an access token.
+// Used in Swagger documentation.
+type LoginRequest struct {
+	Username string `json:"username" example:"david"`
+	Password string `json:"password" example:"1234"`
+}
+
+// LoginResponse is returned after a successful login.
+// Used in Swagger documentation.
+type LoginResponse struct {
+	Token     string    `json:"token"      example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType string    `json:"token_type" example:"Bearer"`
+	ExpiresAt time.Time `json:"expires_at" example:"2025-01-01T00:00:00Z"`
+}
+
 // AddBookRequest represents the payload to add a book into a wishlist.
+// Only the title is required; "author" is kept for older clients and is
+// used when "authors" is empty.
 // Used in Swagger documentation.
 type AddBookRequest struct {
-	Title  string `json:"title"  example:"The Little Prince"`
-	Author string `json:"author" example:"Antoine de Saint-Exupéry"`
+	Title         string   `json:"title"          example:"The Little Prince"`
+	Author        string   `json:"author"         example:"Antoine de Saint-Exupéry"`
+	Authors       []string `json:"authors"        example:"Antoine de Saint-Exupéry"`
+	ISBN10        string   `json:"isbn_10"        example:"0156012197"`
+	ISBN13        string   `json:"isbn_13"        example:"9780156012195"`
+	Publisher     string   `json:"publisher"      example:"Harcourt"`
+	PublishedDate string   `json:"published_date" example:"2000-06-29"`
+	PageCount     int      `json:"page_count"     example:"96"`
+	Language      string   `json:"language"       example:"en"`
+	Categories    []string `json:"categories"     example:"Juvenile Fiction"`
+	Description   string   `json:"description"    example:"A pilot stranded in the desert meets a young prince."`
+	ThumbnailURL  string   `json:"thumbnail_url"  example:"https://books.google.com/books/content?id=abc&printsec=frontcover&img=1"`
+	Notes         string   `json:"notes"          example:"Gift idea for Ana"`
+}
+
+// AddFromGoogleRequest represents the payload to import a Google Books volume into a wishlist.
+// Used in Swagger documentation.
+type AddFromGoogleRequest struct {
+	VolumeID string `json:"volume_id" example:"zyTCAlFPjgYC"`
+}
+
+// UpdateBookRequest represents the payload to replace a book's fields (PUT).
+// Omitted fields are cleared.
+// Used in Swagger documentation.
+type UpdateBookRequest AddBookRequest
+
+// PatchBookRequest represents a partial book update (PATCH).
+// Omitted fields are left unchanged.
+// Used in Swagger documentation.
+type PatchBookRequest struct {
+	Title         *string   `json:"title,omitempty"          example:"The Little Prince"`
+	Author        *string   `json:"author,omitempty"         example:"Antoine de Saint-Exupéry"`
+	Authors       *[]string `json:"authors,omitempty"        example:"Antoine de Saint-Exupéry"`
+	ISBN10        *string   `json:"isbn_10,omitempty"        example:"0156012197"`
+	ISBN13        *string   `json:"isbn_13,omitempty"        example:"9780156012195"`
+	Publisher     *string   `json:"publisher,omitempty"      example:"Harcourt"`
+	PublishedDate *string   `json:"published_date,omitempty" example:"2000-06-29"`
+	PageCount     *int      `json:"page_count,omitempty"     example:"96"`
+	Language      *string   `json:"language,omitempty"       example:"en"`
+	Categories    *[]string `json:"categories,omitempty"     example:"Juvenile Fiction"`
+	Description   *string   `json:"description,omitempty"    example:"A pilot stranded in the desert meets a young prince."`
+	ThumbnailURL  *string   `json:"thumbnail_url,omitempty"  example:"https://books.google.com/books/content?id=abc&printsec=frontcover&img=1"`
+	Notes         *string   `json:"notes,omitempty"          example:"Gift idea for Ana"`
+}
+
+// toBook converts the request into a domain book.
+func (req AddBookRequest) toBook() service.Book {
+	return service.Book{
+		Title:         req.Title,
+		Author:        req.Author,
+		Authors:       req.Authors,
+		ISBN10:        req.ISBN10,
+		ISBN13:        req.ISBN13,
+		Publisher:     req.Publisher,
+		PublishedDate: req.PublishedDate,
+		PageCount:     req.PageCount,
+		Language:      req.Language,
+		Categories:    req.Categories,
+		Description:   req.Description,
+		ThumbnailURL:  req.ThumbnailURL,
+		Notes:         req.Notes,
+	}
+}
+
+// toUpdate converts a full replacement into an update that sets every field.
+func (req UpdateBookRequest) toUpdate() service.BookUpdate {
+	authors := req.Authors
+	if authors == nil {
+		authors = []string{}
+		if req.Author != "" {
+			authors = []string{req.Author}
+		}
+	}
+	categories := req.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+	return service.BookUpdate{
+		Title:         &req.Title,
+		Authors:       &authors,
+		ISBN10:        &req.ISBN10,
+		ISBN13:        &req.ISBN13,
+		Publisher:     &req.Publisher,
+		PublishedDate: &req.PublishedDate,
+		PageCount:     &req.PageCount,
+		Language:      &req.Language,
+		Categories:    &categories,
+		Description:   &req.Description,
+		ThumbnailURL:  &req.ThumbnailURL,
+		Notes:         &req.Notes,
+	}
+}
+
+// toUpdate converts the request into a partial update.
+func (req PatchBookRequest) toUpdate() service.BookUpdate {
+	return service.BookUpdate{
+		Title:         req.Title,
+		Author:        req.Author,
+		Authors:       req.Authors,
+		ISBN10:        req.ISBN10,
+		ISBN13:        req.ISBN13,
+		Publisher:     req.Publisher,
+		PublishedDate: req.PublishedDate,
+		PageCount:     req.PageCount,
+		Language:      req.Language,
+		Categories:    req.Categories,
+		Description:   req.Description,
+		ThumbnailURL:  req.ThumbnailURL,
+		Notes:         req.Notes,
+	}
 }
 
 //
@@ -48,6 +191,27 @@ type BookHTTP struct {
 	book service.BookUsecase
 }
 
+// AuthHTTP groups endpoints related to authentication.
+type AuthHTTP struct {
+	users  service.UserUsecase
+	tokens *auth.Manager
+}
+
+// EnrichmentHTTP groups endpoints related to book metadata enrichment.
+type EnrichmentHTTP struct {
+	enrich service.EnrichmentUsecase
+}
+
+// WishlistSearchHTTP groups endpoints that search the caller's own wishlists.
+type WishlistSearchHTTP struct {
+	search service.WishlistSearchUsecase
+}
+
+// TrashHTTP groups endpoints related to deleted wishlists and books.
+type TrashHTTP struct {
+	trash service.TrashUsecase
+}
+
 //
 // ───────────────────────── CONSTRUCTORS ─────────────────────────
 //
@@ -62,6 +226,65 @@ func NewBookHTTP(b service.BookUsecase) *BookHTTP {
 	return &BookHTTP{book: b}
 }
 
+// NewAuthHTTP builds a handler for authentication endpoints.
+func NewAuthHTTP(u service.UserUsecase, tokens *auth.Manager) *AuthHTTP {
+	return &AuthHTTP{users: u, tokens: tokens}
+}
+
+// NewEnrichmentHTTP builds a handler for metadata enrichment endpoints.
+func NewEnrichmentHTTP(e service.EnrichmentUsecase) *EnrichmentHTTP {
+	return &EnrichmentHTTP{enrich: e}
+}
+
+// NewWishlistSearchHTTP builds a handler for searching the caller's wishlists.
+func NewWishlistSearchHTTP(s service.WishlistSearchUsecase) *WishlistSearchHTTP {
+	return &WishlistSearchHTTP{search: s}
+}
+
+// NewTrashHTTP builds a handler for the trash and restore endpoints.
+func NewTrashHTTP(t service.TrashUsecase) *TrashHTTP {
+	return &TrashHTTP{trash: t}
+}
+
+//
+// ───────────────────────── HELPERS ─────────────────────────
+//
+
+// userIDFromRequest returns the authenticated user ID set by auth.Middleware.
+// It writes a 401 response and returns false when no user is present.
+func userIDFromRequest(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return 0, false
+	}
+	return userID, true
+}
+
+// pathID parses the named path variable as a positive ID.
+// It writes a 400 response with msg and returns false when the value is invalid.
+func pathID(w http.ResponseWriter, r *http.Request, name, msg string) (uint, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)[name], 10, 0)
+	if err != nil || id == 0 {
+		writeError(w, http.StatusBadRequest, msg)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// bookPath resolves the caller and the {id}/{bookID} path variables shared by
+// single-book endpoints, writing the error response when any is missing.
+func bookPath(w http.ResponseWriter, r *http.Request) (userID, wishlistID, bookID uint, ok bool) {
+	if userID, ok = userIDFromRequest(w, r); !ok {
+		return
+	}
+	if wishlistID, ok = pathID(w, r, "id", "invalid wishlist id"); !ok {
+		return
+	}
+	bookID, ok = pathID(w, r, "bookID", "invalid book id")
+	return
+}
+
 //
 // ───────────────────────── USERS ─────────────────────────
 //
@@ -73,36 +296,108 @@ func NewBookHTTP(b service.BookUsecase) *BookHTTP {
 // @Produce json
 // @Param user body RegisterUserRequest true "User data"
 // @Success 201
-// @Failure 400
-// @Failure 500
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /users/register [post]
 func (h *HTTPHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	var req RegisterUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid payload", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "invalid payload")
 		return
 	}
-	if err := h.users.Register(req.Username, req.Password); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.users.Register(r.Context(), req.Username, req.Password); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
 }
 
-// ListUsers handles GET /users
-// @Summary List registered users
+// ListUsers handles GET /users. Register it behind RequireAdmin.
+// @Summary List registered users (admin only)
+// @Description Results are paged with opaque cursors: when more users follow, the response carries
+// @Description a Link header with rel="next" and the cursor of the next page in X-Next-Cursor.
 // @Tags users
 // @Produce json
-// @Success 200 {array} service.User
+// @Param limit query int false "Page size, 1-200 (default 50)"
+// @Param cursor query string false "X-Next-Cursor of the previous page"
+// @Param sort query string false "created (default) or username; prefix with - for descending"
+// @Param username query string false "Only users whose username contains this text"
+// @Success 200 {array} UserResponse
+// @Header 200 {string} Link "URL of the next page (rel=next)"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /users [get]
 func (h *HTTPHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.users.List()
+	opts, ok := listOptions(w, r, "username")
+	if !ok {
+		return
+	}
+	page, err := h.users.List(r.Context(), opts)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(users)
+	setNextPage(w, r, page.NextCursor)
+	writeJSON(w, http.StatusOK, toUserResponses(page.Items))
+}
+
+// Me handles GET /users/me
+// @Summary Get the authenticated user
+// @Tags users
+// @Produce json
+// @Success 200 {object} UserResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /users/me [get]
+func (h *HTTPHandler) Me(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	user, err := h.users.Get(r.Context(), userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toUserResponse(*user))
+}
+
+// Login handles POST /users/login
+// @Summary Log in and obtain a JWT access token
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param credentials body LoginRequest true "User credentials"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/login [post]
+func (h *AuthHTTP) Login(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	user, err := h.users.Authenticate(r.Context(), req.Username, req.Password)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	token, exp, err := h.tokens.Issue(user.ID, user.Username)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, LoginResponse{Token: token, TokenType: "Bearer", ExpiresAt: exp})
 }
 
 //
@@ -115,58 +410,191 @@ func (h *HTTPHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param data body CreateWishlistRequest true "Wishlist data"
-// @Success 201
-// @Failure 400
+// @Success 201 {object} WishlistResponse
+// @Header 201 {string} Location "URL of the new wishlist"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /wishlist [post]
 func (h *HTTPHandler) CreateWishlist(w http.ResponseWriter, r *http.Request) {
 	var req CreateWishlistRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid payload", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "invalid payload")
 		return
 	}
-	userID := uint(1) // In real scenarios, this would come from JWT claims
-	if err := h.wishlist.Create(userID, req.Name); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
 		return
 	}
-	w.WriteHeader(http.StatusCreated)
+	list, err := h.wishlist.Create(r.Context(), userID, req.Name)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/api/wishlist/%d", list.ID))
+	writeJSON(w, http.StatusCreated, toWishlistResponse(*list))
 }
 
 // ListWishlists handles GET /wishlist
 // @Summary List all wishlists for a user
+// @Description Results are paged with opaque cursors: when more wishlists follow, the response carries
+// @Description a Link header with rel="next" and the cursor of the next page in X-Next-Cursor.
 // @Tags wishlist
 // @Produce json
-// @Success 200 {array} service.Wishlist
+// @Param limit query int false "Page size, 1-200 (default 50)"
+// @Param cursor query string false "X-Next-Cursor of the previous page"
+// @Param sort query string false "created (default) or name; prefix with - for descending"
+// @Param name query string false "Only wishlists whose name contains this text"
+// @Success 200 {array} WishlistResponse
+// @Header 200 {string} Link "URL of the next page (rel=next)"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /wishlist [get]
 func (h *HTTPHandler) ListWishlists(w http.ResponseWriter, r *http.Request) {
-	userID := uint(1) // Hardcoded for now, should come from JWT
-	lists, err := h.wishlist.List(userID)
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	opts, ok := listOptions(w, r, "name")
+	if !ok {
+		return
+	}
+	page, err := h.wishlist.List(r.Context(), userID, opts)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(lists)
+	setNextPage(w, r, page.NextCursor)
+	writeJSON(w, http.StatusOK, toWishlistResponses(page.Items))
+}
+
+// GetWishlist handles GET /wishlist/{id}
+// @Summary Get a wishlist by ID
+// @Tags wishlist
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Success 200 {object} WishlistResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wishlist/{id} [get]
+func (h *HTTPHandler) GetWishlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id", "invalid id")
+	if !ok {
+		return
+	}
+	list, err := h.wishlist.Get(r.Context(), userID, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toWishlistResponse(*list))
+}
+
+// UpdateWishlist handles PUT /wishlist/{id}
+// @Summary Replace a wishlist (rename)
+// @Tags wishlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Param data body UpdateWishlistRequest true "Wishlist data"
+// @Success 200 {object} WishlistResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wishlist/{id} [put]
+func (h *HTTPHandler) UpdateWishlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id", "invalid id")
+	if !ok {
+		return
+	}
+	var req UpdateWishlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	list, err := h.wishlist.Update(r.Context(), userID, id, service.WishlistUpdate{Name: &req.Name})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toWishlistResponse(*list))
+}
+
+// PatchWishlist handles PATCH /wishlist/{id}
+// @Summary Partially update a wishlist
+// @Tags wishlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Param data body PatchWishlistRequest true "Fields to change"
+// @Success 200 {object} WishlistResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wishlist/{id} [patch]
+func (h *HTTPHandler) PatchWishlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id", "invalid id")
+	if !ok {
+		return
+	}
+	var req PatchWishlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	list, err := h.wishlist.Update(r.Context(), userID, id, service.WishlistUpdate{Name: req.Name})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toWishlistResponse(*list))
 }
 
 // DeleteWishlist handles DELETE /wishlist/{id}
 // @Summary Delete a wishlist by ID
+// @Description Moves the wishlist and its books to the trash, from which it can be restored
+// @Description until it is purged (see GET /trash).
 // @Tags wishlist
 // @Param id path int true "Wishlist ID"
 // @Success 204
-// @Failure 400
-// @Failure 500
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /wishlist/{id} [delete]
 func (h *HTTPHandler) DeleteWishlist(w http.ResponseWriter, r *http.Request) {
-	userID := uint(1) // Hardcoded for now
-	idStr := mux.Vars(r)["id"]
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		http.Error(w, "invalid id", http.StatusBadRequest)
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id", "invalid id")
+	if !ok {
 		return
 	}
-	if err := h.wishlist.Delete(userID, uint(id)); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.wishlist.Delete(r.Context(), userID, id); err != nil {
+		writeServiceError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -182,125 +610,693 @@ func (h *HTTPHandler) DeleteWishlist(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Wishlist ID"
+// @Description If the wishlist already holds the book (same ISBN, or same title and author
+// @Description ignoring case and accents), on_duplicate decides the outcome: "reject" (default)
+// @Description returns 409, "merge" fills the stored book's empty fields and returns it with 200,
+// @Description and "allow" adds the copy anyway.
 // @Param data body AddBookRequest true "Book data"
-// @Success 201
-// @Failure 400
-// @Failure 500
+// @Param on_duplicate query string false "Duplicate policy" Enums(reject, merge, allow) default(reject)
+// @Success 200 {object} BookResponse "Merged into an existing book"
+// @Success 201 {object} BookResponse
+// @Header 200,201 {string} Location "URL of the book"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /wishlist/{id}/books [post]
 func (h *BookHTTP) AddBook(w http.ResponseWriter, r *http.Request) {
-	wishlistIDStr := mux.Vars(r)["id"]
-	wishlistID, err := strconv.Atoi(wishlistIDStr)
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	wishlistID, ok := pathID(w, r, "id", "invalid wishlist id")
+	if !ok {
+		return
+	}
+
+	policy, err := service.ParseDuplicatePolicy(r.URL.Query().Get("on_duplicate"))
 	if err != nil {
-		http.Error(w, "invalid wishlist id", http.StatusBadRequest)
+		writeServiceError(w, err)
 		return
 	}
 
 	var req AddBookRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid payload", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "invalid payload")
 		return
 	}
 
-	if err := h.book.Add(uint(wishlistID), req.Title, req.Author); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	book, created, err := h.book.Add(r.Context(), userID, wishlistID, req.toBook(), policy)
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
-	w.WriteHeader(http.StatusCreated)
+	writeAddedBook(w, wishlistID, book, created)
+}
+
+// AddFromGoogle handles POST /wishlist/{id}/books/from-google
+// @Summary Add a book from a Google Books volume
+// @Description Fetches the volume from Google Books and stores it with its full metadata and
+// @Description volume ID. Duplicates are handled as in AddBook (see on_duplicate).
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Param data body AddFromGoogleRequest true "Google Books volume"
+// @Param on_duplicate query string false "Duplicate policy" Enums(reject, merge, allow) default(reject)
+// @Success 200 {object} BookResponse "Merged into an existing book"
+// @Success 201 {object} BookResponse
+// @Header 200,201 {string} Location "URL of the book"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "Wishlist or volume not found"
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse "Google Books quota exceeded"
+// @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse "Google Books rejected the request"
+// @Failure 503 {object} ErrorResponse "Google Books unavailable"
+// @Security BearerAuth
+// @Router /wishlist/{id}/books/from-google [post]
+func (h *BookHTTP) AddFromGoogle(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	wishlistID, ok := pathID(w, r, "id", "invalid wishlist id")
+	if !ok {
+		return
+	}
+	policy, err := service.ParseDuplicatePolicy(r.URL.Query().Get("on_duplicate"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	var req AddFromGoogleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+
+	book, created, err := h.book.AddFromGoogle(r.Context(), userID, wishlistID, req.VolumeID, policy)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeAddedBook(w, wishlistID, book, created)
+}
+
+// writeAddedBook writes the result of adding a book: 201 for a new book or
+// 200 when it was merged into an existing one, with a Location header either way.
+func writeAddedBook(w http.ResponseWriter, wishlistID uint, book *service.Book, created bool) {
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	w.Header().Set("Location", fmt.Sprintf("/api/wishlist/%d/books/%d", wishlistID, book.ID))
+	writeJSON(w, status, toBookResponse(*book))
 }
 
 // ListBooks handles GET /wishlist/{id}/books
 // @Summary List all books from a wishlist
+// @Description Results are paged with opaque cursors: when more books follow, the response carries
+// @Description a Link header with rel="next" and the cursor of the next page in X-Next-Cursor.
 // @Tags books
 // @Produce json
 // @Param id path int true "Wishlist ID"
-// @Success 200 {array} service.Book
+// @Param limit query int false "Page size, 1-200 (default 50)"
+// @Param cursor query string false "X-Next-Cursor of the previous page"
+// @Param sort query string false "created (default), title or author; prefix with - for descending"
+// @Param title query string false "Only books whose title contains this text"
+// @Param author query string false "Only books with an author whose name contains this text"
+// @Success 200 {array} BookResponse
+// @Header 200 {string} Link "URL of the next page (rel=next)"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /wishlist/{id}/books [get]
 func (h *BookHTTP) ListBooks(w http.ResponseWriter, r *http.Request) {
-	wishlistIDStr := mux.Vars(r)["id"]
-	wishlistID, err := strconv.Atoi(wishlistIDStr)
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	wishlistID, ok := pathID(w, r, "id", "invalid wishlist id")
+	if !ok {
+		return
+	}
+
+	opts, ok := listOptions(w, r, "title", "author")
+	if !ok {
+		return
+	}
+	page, err := h.book.List(r.Context(), userID, wishlistID, opts)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setNextPage(w, r, page.NextCursor)
+	writeJSON(w, http.StatusOK, toBookResponses(page.Items))
+}
+
+// ListDuplicates handles GET /wishlist/{id}/duplicates
+// @Summary List likely duplicate books in a wishlist
+// @Description Groups books that share an ISBN, or (when either has no ISBN) the same title
+// @Description and author ignoring case, accents and punctuation.
+// @Tags books
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Success 200 {array} DuplicateGroupResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wishlist/{id}/duplicates [get]
+func (h *BookHTTP) ListDuplicates(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	wishlistID, ok := pathID(w, r, "id", "invalid wishlist id")
+	if !ok {
+		return
+	}
+
+	groups, err := h.book.Duplicates(r.Context(), userID, wishlistID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toDuplicateGroupResponses(groups))
+}
+
+// EnrichBooks handles POST /wishlist/{id}/books/enrich
+// @Summary Fill in missing book metadata from Google Books
+// @Description Starts a background job that looks up every book of the wishlist lacking an ISBN
+// @Description or a cover. Empty fields are filled in when the match confidence is high enough and
+// @Description listed in auto_filled. Poll the URL in the Location header for progress; if a job
+// @Description for the wishlist is already running, that job is returned.
+// @Tags books
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Success 202 {object} EnrichJobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wishlist/{id}/books/enrich [post]
+func (h *EnrichmentHTTP) EnrichBooks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	wishlistID, ok := pathID(w, r, "id", "invalid wishlist id")
+	if !ok {
+		return
+	}
+
+	job, err := h.enrich.Enrich(r.Context(), userID, wishlistID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/api/wishlist/%d/books/enrich/%s", wishlistID, job.ID))
+	writeJSON(w, http.StatusAccepted, toEnrichJobResponse(job))
+}
+
+// GetEnrichJob handles GET /wishlist/{id}/books/enrich/{jobID}
+// @Summary Get the progress of a metadata enrichment job
+// @Description Finished jobs can be polled for an hour (ENRICH_JOB_RETENTION).
+// @Tags books
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Param jobID path string true "Job ID"
+// @Success 200 {object} EnrichJobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wishlist/{id}/books/enrich/{jobID} [get]
+func (h *EnrichmentHTTP) GetEnrichJob(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	wishlistID, ok := pathID(w, r, "id", "invalid wishlist id")
+	if !ok {
+		return
+	}
+
+	job, err := h.enrich.Job(r.Context(), userID, mux.Vars(r)["jobID"])
+	if err == nil && job.WishlistID != wishlistID {
+		err = service.ErrEnrichJobNotFound
+	}
 	if err != nil {
-		http.Error(w, "invalid wishlist id", http.StatusBadRequest)
+		writeServiceError(w, err)
 		return
 	}
+	writeJSON(w, http.StatusOK, toEnrichJobResponse(job))
+}
 
-	books, err := h.book.List(uint(wishlistID))
+// GetBook handles GET /wishlist/{id}/books/{bookID}
+// @Summary Get a book from a wishlist
+// @Tags books
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Param bookID path int true "Book ID"
+// @Success 200 {object} BookResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wishlist/{id}/books/{bookID} [get]
+func (h *BookHTTP) GetBook(w http.ResponseWriter, r *http.Request) {
+	userID, wishlistID, bookID, ok := bookPath(w, r)
+	if !ok {
+		return
+	}
+	book, err := h.book.Get(r.Context(), userID, wishlistID, bookID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(books)
+	writeJSON(w, http.StatusOK, toBookResponse(*book))
+}
+
+// UpdateBook handles PUT /wishlist/{id}/books/{bookID}
+// @Summary Replace a book's editable fields
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Param bookID path int true "Book ID"
+// @Param data body UpdateBookRequest true "Book data"
+// @Success 200 {object} BookResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wishlist/{id}/books/{bookID} [put]
+func (h *BookHTTP) UpdateBook(w http.ResponseWriter, r *http.Request) {
+	userID, wishlistID, bookID, ok := bookPath(w, r)
+	if !ok {
+		return
+	}
+	var req UpdateBookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	upd := req.toUpdate()
+	book, err := h.book.Update(r.Context(), userID, wishlistID, bookID, upd)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toBookResponse(*book))
+}
+
+// PatchBook handles PATCH /wishlist/{id}/books/{bookID}
+// @Summary Partially update a book
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Param bookID path int true "Book ID"
+// @Param data body PatchBookRequest true "Fields to change"
+// @Success 200 {object} BookResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wishlist/{id}/books/{bookID} [patch]
+func (h *BookHTTP) PatchBook(w http.ResponseWriter, r *http.Request) {
+	userID, wishlistID, bookID, ok := bookPath(w, r)
+	if !ok {
+		return
+	}
+	var req PatchBookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	upd := req.toUpdate()
+	book, err := h.book.Update(r.Context(), userID, wishlistID, bookID, upd)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toBookResponse(*book))
 }
 
 // DeleteBook handles DELETE /wishlist/{id}/books/{bookID}
 // @Summary Remove a book from a wishlist
+// @Description Moves the book to the trash, from which it can be restored until it is purged
+// @Description (see GET /trash).
 // @Tags books
 // @Param id path int true "Wishlist ID"
 // @Param bookID path int true "Book ID"
 // @Success 204
-// @Failure 400
-// @Failure 500
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
 // @Router /wishlist/{id}/books/{bookID} [delete]
 func (h *BookHTTP) DeleteBook(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	wishlistID, err := strconv.Atoi(vars["id"])
+	userID, wishlistID, bookID, ok := bookPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.book.Delete(r.Context(), userID, wishlistID, bookID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CheckISBN handles GET /isbn/{isbn}
+// @Summary Validate and convert an ISBN
+// @Description Strips hyphens and spaces, verifies the check digit and returns both the
+// @Description ISBN-10 and canonical ISBN-13 forms. Invalid input is reported with valid=false.
+// @Tags books
+// @Produce json
+// @Param isbn path string true "ISBN-10 or ISBN-13, hyphens allowed"
+// @Success 200 {object} ISBNResponse
+// @Router /isbn/{isbn} [get]
+func (h *BookHTTP) CheckISBN(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, toISBNResponse(service.CheckISBN(mux.Vars(r)["isbn"])))
+}
+
+//
+// ───────────────────────── SEARCH ─────────────────────────
+//
+
+// Search handles GET /search
+// @Summary Search the books in all of the caller's wishlists
+// @Description Finds books whose title, authors, description or notes contain every word of q,
+// @Description as a word or the start of one, ignoring case and accents. Punctuation only
+// @Description separates words. Hits are ranked best first; title and author matches weigh most.
+// @Description highlights holds the matching fields as HTML-escaped text with the matched words
+// @Description wrapped in <mark>; description and notes are shortened to the matching passage.
+// @Tags search
+// @Produce json
+// @Param q query string true "Words to find"
+// @Param limit query int false "Maximum number of hits, 1-100 (default 20)"
+// @Success 200 {array} SearchHitResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /search [get]
+func (h *WishlistSearchHTTP) Search(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	limit, ok := queryInt(w, q, "limit")
+	if !ok {
+		return
+	}
+	if q.Has("limit") && limit < 1 {
+		writeError(w, http.StatusBadRequest, "limit must be positive")
+		return
+	}
+
+	hits, err := h.search.Search(r.Context(), userID, q.Get("q"), limit)
 	if err != nil {
-		http.Error(w, "invalid wishlist id", http.StatusBadRequest)
+		writeServiceError(w, err)
 		return
 	}
-	bookID, err := strconv.Atoi(vars["bookID"])
+	writeJSON(w, http.StatusOK, toSearchHitResponses(hits))
+}
+
+//
+// ───────────────────────── TRASH ─────────────────────────
+//
+
+// ListTrash handles GET /trash
+// @Summary List the caller's deleted wishlists and books
+// @Description Deleted items are kept for a retention period (TRASH_RETENTION, 30 days by default)
+// @Description and permanently deleted afterwards; purge_at tells when. Books of a deleted wishlist
+// @Description are not listed on their own: restoring the wishlist brings them back.
+// @Tags trash
+// @Produce json
+// @Success 200 {object} TrashResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /trash [get]
+func (h *TrashHTTP) ListTrash(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	trash, err := h.trash.List(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "invalid book id", http.StatusBadRequest)
+		writeServiceError(w, err)
 		return
 	}
+	writeJSON(w, http.StatusOK, toTrashResponse(trash))
+}
 
-	if err := h.book.Delete(uint(wishlistID), uint(bookID)); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+// RestoreWishlist handles POST /wishlist/{id}/restore
+// @Summary Restore a deleted wishlist
+// @Description Takes the wishlist out of the trash together with the books it held when it was
+// @Description deleted. Books deleted on their own before that stay in the trash.
+// @Tags trash
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Success 200 {object} WishlistResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wishlist/{id}/restore [post]
+func (h *TrashHTTP) RestoreWishlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromRequest(w, r)
+	if !ok {
 		return
 	}
-	w.WriteHeader(http.StatusNoContent)
+	id, ok := pathID(w, r, "id", "invalid id")
+	if !ok {
+		return
+	}
+	list, err := h.trash.RestoreWishlist(r.Context(), userID, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toWishlistResponse(*list))
+}
+
+// RestoreBook handles POST /wishlist/{id}/books/{bookID}/restore
+// @Summary Restore a deleted book
+// @Description Takes the book out of the trash, back into its wishlist. A book of a deleted
+// @Description wishlist comes back by restoring the wishlist instead.
+// @Tags trash
+// @Produce json
+// @Param id path int true "Wishlist ID"
+// @Param bookID path int true "Book ID"
+// @Success 200 {object} BookResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /wishlist/{id}/books/{bookID}/restore [post]
+func (h *TrashHTTP) RestoreBook(w http.ResponseWriter, r *http.Request) {
+	userID, wishlistID, bookID, ok := bookPath(w, r)
+	if !ok {
+		return
+	}
+	book, err := h.trash.RestoreBook(r.Context(), userID, wishlistID, bookID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toBookResponse(*book))
 }
 
 //
 // ───────────────────────── GOOGLE BOOKS ─────────────────────────
 //
 
-// GoogleBooksHTTP handles external Google Books searches.
+// GoogleBooksHTTP handles catalogue searches and Google Books volume lookups.
 type GoogleBooksHTTP struct {
-	api service.GoogleBooksUsecase
+	api     service.GoogleBooksUsecase
+	catalog service.CatalogUsecase
 }
 
-// NewGoogleBooksHTTP builds the Google Books handler.
-func NewGoogleBooksHTTP(api service.GoogleBooksUsecase) *GoogleBooksHTTP {
-	return &GoogleBooksHTTP{api: api}
+// NewGoogleBooksHTTP builds the Google Books handler. Searches go through
+// catalog, which selects the provider; volume lookups always use api.
+func NewGoogleBooksHTTP(api service.GoogleBooksUsecase, catalog service.CatalogUsecase) *GoogleBooksHTTP {
+	return &GoogleBooksHTTP{api: api, catalog: catalog}
 }
 
-// RegisterGoogleRoutes registers Google Books API routes under /books.
+// RegisterGoogleRoutes registers the public Google Books API routes under /books.
+// CacheStats is left out: register it behind RequireAdmin.
 func (h *GoogleBooksHTTP) RegisterGoogleRoutes(r *mux.Router) {
 	r.HandleFunc("/books/search", h.SearchBooks).Methods(http.MethodGet)
+	r.HandleFunc("/books/volumes/{volumeID}", h.GetVolume).Methods(http.MethodGet)
 }
 
 // SearchBooks handles GET /books/search
-// @Summary Search books using Google Books API
+// @Summary Search books in Google Books, Open Library or both
+// @Description At least one of q, title, author, isbn or subject is required. The field
+// @Description filters are sent to Google as intitle:, inauthor:, isbn: and subject: terms.
+// @Description provider=aggregate queries every provider concurrently, merges volumes that
+// @Description share an ISBN and reports the provider of each field in field_sources.
 // @Tags books
 // @Produce json
-// @Param q query string true "Search term"
-// @Success 200 {array} service.GoogleBook
-// @Failure 400
-// @Failure 500
+// @Param provider query string false "Catalogue to search (default: google_books)" Enums(google_books, open_library, aggregate)
+// @Param q query string false "Free-text search terms"
+// @Param title query string false "Words in the title"
+// @Param author query string false "Words in the author name"
+// @Param isbn query string false "ISBN-10 or ISBN-13"
+// @Param subject query string false "Subject or category"
+// @Param startIndex query int false "Zero-based index of the first result" minimum(0) default(0)
+// @Param maxResults query int false "Page size" minimum(1) maximum(40) default(10)
+// @Param orderBy query string false "Sort order" Enums(relevance, newest)
+// @Param printType query string false "Restrict by print type" Enums(all, books, magazines)
+// @Param language query string false "Two-letter ISO 639-1 language code"
+// @Success 200 {object} SearchResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse "Google Books quota exceeded"
+// @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse "Google Books rejected the request"
+// @Failure 503 {object} ErrorResponse "Google Books unavailable"
 // @Router /books/search [get]
 func (h *GoogleBooksHTTP) SearchBooks(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	if query == "" {
-		http.Error(w, "missing query param q", http.StatusBadRequest)
+	q := r.URL.Query()
+	params := service.SearchParams{
+		Query:     q.Get("q"),
+		Title:     q.Get("title"),
+		Author:    q.Get("author"),
+		ISBN:      q.Get("isbn"),
+		Subject:   q.Get("subject"),
+		OrderBy:   q.Get("orderBy"),
+		PrintType: q.Get("printType"),
+		Language:  q.Get("language"),
+	}
+	var ok bool
+	if params.StartIndex, ok = queryInt(w, q, "startIndex"); !ok {
+		return
+	}
+	if params.MaxResults, ok = queryInt(w, q, "maxResults"); !ok {
+		return
+	}
+
+	result, err := h.catalog.Search(r.Context(), q.Get("provider"), params)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toSearchResponse(result, r.URL))
+}
+
+// GetVolume handles GET /books/volumes/{volumeID}
+// @Summary Get a Google Books volume
+// @Tags books
+// @Produce json
+// @Param volumeID path string true "Google Books volume ID"
+// @Success 200 {object} VolumeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse "Google Books quota exceeded"
+// @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse "Google Books rejected the request"
+// @Failure 503 {object} ErrorResponse "Google Books unavailable"
+// @Router /books/volumes/{volumeID} [get]
+func (h *GoogleBooksHTTP) GetVolume(w http.ResponseWriter, r *http.Request) {
+	book, err := h.api.GetVolume(r.Context(), mux.Vars(r)["volumeID"])
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
+	writeJSON(w, http.StatusOK, toVolumeResponse(*book))
+}
 
-	results, err := h.api.Search(query)
+// CacheStats handles GET /books/cache/stats. Register it behind RequireAdmin.
+// @Summary Google Books cache counters (admin only)
+// @Description Hit, miss and coalescing counters of the Google Books cache, for monitoring.
+// @Tags books
+// @Produce json
+// @Success 200 {object} CacheStatsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "Caching is disabled"
+// @Security BearerAuth
+// @Router /books/cache/stats [get]
+func (h *GoogleBooksHTTP) CacheStats(w http.ResponseWriter, r *http.Request) {
+	cache, ok := h.api.(service.CacheStatsProvider)
+	if !ok {
+		writeError(w, http.StatusNotFound, "google books cache is disabled")
+		return
+	}
+	writeJSON(w, http.StatusOK, toCacheStatsResponse(cache.CacheStats()))
+}
+
+// queryInt parses an optional integer query parameter, returning 0 when absent.
+// It writes a 400 response and returns false if the value is not an integer.
+func queryInt(w http.ResponseWriter, q url.Values, name string) (int, bool) {
+	v := q.Get(name)
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(v)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusBadRequest, "invalid "+name)
+		return 0, false
+	}
+	return n, true
+}
+
+// listOptions reads the limit, cursor and sort query parameters of a list
+// endpoint together with the given filter parameters. A leading "-" on sort
+// selects descending order. It writes a 400 response and returns false if
+// limit is not a positive integer.
+func listOptions(w http.ResponseWriter, r *http.Request, filters ...string) (service.ListOptions, bool) {
+	q := r.URL.Query()
+	limit, ok := queryInt(w, q, "limit")
+	if !ok {
+		return service.ListOptions{}, false
+	}
+	if q.Has("limit") && limit < 1 {
+		writeError(w, http.StatusBadRequest, "limit must be positive")
+		return service.ListOptions{}, false
+	}
+	opts := service.ListOptions{Limit: limit, Cursor: q.Get("cursor"), Filters: map[string]string{}}
+	opts.Sort, opts.Desc = strings.CutPrefix(q.Get("sort"), "-")
+	for _, name := range filters {
+		if v := q.Get(name); v != "" {
+			opts.Filters[name] = v
+		}
+	}
+	return opts, true
+}
+
+// setNextPage advertises the next page of a list, if any, through the
+// X-Next-Cursor header and a Link header that repeats the request's query
+// parameters with the new cursor.
+func setNextPage(w http.ResponseWriter, r *http.Request, cursor string) {
+	if cursor == "" {
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
+	q := r.URL.Query()
+	q.Set("cursor", cursor)
+	w.Header().Set("X-Next-Cursor", cursor)
+	w.Header().Set("Link", fmt.Sprintf("<%s?%s>; rel=\"next\"", r.URL.Path, q.Encode()))
 }
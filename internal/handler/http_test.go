@@ -2,13 +2,20 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/deividmendozatech-stack/wishlist/pkg/auth"
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
 )
 
 //
@@ -20,20 +27,66 @@ type mockWishlist struct{}
 
 var _ service.WishlistUsecase = (*mockWishlist)(nil)
 
-func (m *mockWishlist) Create(userID uint, name string) error { return nil }
-func (m *mockWishlist) List(userID uint) ([]service.Wishlist, error) {
-	return []service.Wishlist{{ID: 1, UserID: userID, Name: "TestList"}}, nil
+func (m *mockWishlist) Create(ctx context.Context, userID uint, name string) (*service.Wishlist, error) {
+	return &service.Wishlist{ID: 7, UserID: userID, Name: name}, nil
+}
+func (m *mockWishlist) Get(ctx context.Context, userID, id uint) (*service.Wishlist, error) {
+	if id != userID {
+		return nil, service.ErrWishlistNotFound
+	}
+	return &service.Wishlist{ID: id, UserID: userID, Name: "TestList"}, nil
+}
+func (m *mockWishlist) Update(ctx context.Context, userID, id uint, upd service.WishlistUpdate) (*service.Wishlist, error) {
+	wl, err := m.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if upd.Name != nil {
+		if *upd.Name == "" {
+			return nil, service.NewError(service.ErrValidation, "wishlist name cannot be empty")
+		}
+		wl.Name = *upd.Name
+	}
+	return wl, nil
+}
+func (m *mockWishlist) List(ctx context.Context, userID uint, opts service.ListOptions) (*service.Page[service.Wishlist], error) {
+	return &service.Page[service.Wishlist]{Items: []service.Wishlist{{ID: 1, UserID: userID, Name: "TestList"}}}, nil
+}
+func (m *mockWishlist) Delete(ctx context.Context, userID, id uint) error {
+	if id == 999 {
+		return service.ErrWishlistNotFound
+	}
+	return nil
 }
-func (m *mockWishlist) Delete(userID, id uint) error { return nil }
 
 // mockUser is a mock implementation of UserUsecase for testing purposes.
 type mockUser struct{}
 
 var _ service.UserUsecase = (*mockUser)(nil)
 
-func (m *mockUser) Register(username, password string) error { return nil }
-func (m *mockUser) List() ([]service.User, error) {
-	return []service.User{{ID: 1, Username: "david"}}, nil
+func (m *mockUser) Register(ctx context.Context, username, password string) error {
+	if username == "taken" {
+		return service.ErrUsernameTaken
+	}
+	return nil
+}
+func (m *mockUser) List(ctx context.Context, opts service.ListOptions) (*service.Page[service.User], error) {
+	return &service.Page[service.User]{Items: []service.User{{ID: 1, Username: "david"}}}, nil
+}
+func (m *mockUser) Get(ctx context.Context, userID uint) (*service.User, error) {
+	switch userID {
+	case 1:
+		return &service.User{ID: 1, Username: "david", Password: "hash", IsAdmin: true}, nil
+	case 2:
+		return &service.User{ID: 2, Username: "alice", Password: "hash"}, nil
+	}
+	return nil, service.ErrUserNotFound
+}
+func (m *mockUser) Authenticate(ctx context.Context, username, password string) (*service.User, error) {
+	if username != "david" || password != "1234" {
+		return nil, service.ErrInvalidCredentials
+	}
+	return &service.User{ID: 1, Username: "david"}, nil
 }
 
 // mockBook is a mock implementation of BookUsecase for testing purposes.
@@ -41,16 +94,177 @@ type mockBook struct{}
 
 var _ service.BookUsecase = (*mockBook)(nil)
 
-func (m *mockBook) Add(wishlistID uint, title, author string) error { return nil }
-func (m *mockBook) List(wishlistID uint) ([]service.Book, error) {
-	return []service.Book{{ID: 1, WishlistID: wishlistID, Title: "BookTest", Author: "Anon"}}, nil
+// checkOwner mirrors the service rule: wishlist 1 belongs to user 1,
+// wishlist 2 to user 2, and any other ID does not exist.
+func (m *mockBook) checkOwner(userID, wishlistID uint) error {
+	if wishlistID != userID {
+		return service.ErrWishlistNotFound
+	}
+	return nil
+}
+
+// Add treats the title "Dup" as already stored in the wishlist as book 5.
+func (m *mockBook) Add(ctx context.Context, userID, wishlistID uint, b service.Book, policy service.DuplicatePolicy) (*service.Book, bool, error) {
+	if err := m.checkOwner(userID, wishlistID); err != nil {
+		return nil, false, err
+	}
+	if b.Title == "Dup" && policy == service.DuplicateReject {
+		return nil, false, service.ErrDuplicateBook
+	}
+	if b.Title == "Dup" && policy == service.DuplicateMerge {
+		return &service.Book{ID: 5, WishlistID: wishlistID, Title: b.Title}, false, nil
+	}
+	b.ID, b.WishlistID = 3, wishlistID
+	return &b, true, nil
+}
+
+// AddFromGoogle knows only the volume "vol1".
+func (m *mockBook) AddFromGoogle(ctx context.Context, userID, wishlistID uint, volumeID string, policy service.DuplicatePolicy) (*service.Book, bool, error) {
+	if err := m.checkOwner(userID, wishlistID); err != nil {
+		return nil, false, err
+	}
+	if volumeID != "vol1" {
+		return nil, false, service.ErrVolumeNotFound
+	}
+	return &service.Book{ID: 4, WishlistID: wishlistID, Title: "Go", ExternalSource: service.SourceGoogleBooks, ExternalID: volumeID}, true, nil
+}
+func (m *mockBook) Duplicates(ctx context.Context, userID, wishlistID uint) ([]service.DuplicateGroup, error) {
+	if err := m.checkOwner(userID, wishlistID); err != nil {
+		return nil, err
+	}
+	return []service.DuplicateGroup{{
+		MatchedBy: []string{service.MatchISBN},
+		Books:     []service.Book{{ID: 1, WishlistID: wishlistID, Title: "Go"}, {ID: 2, WishlistID: wishlistID, Title: "Go"}},
+	}}, nil
+}
+func (m *mockBook) Get(ctx context.Context, userID, wishlistID, bookID uint) (*service.Book, error) {
+	if err := m.checkOwner(userID, wishlistID); err != nil {
+		return nil, err
+	}
+	if bookID == 999 {
+		return nil, service.ErrBookNotFound
+	}
+	return &service.Book{ID: bookID, WishlistID: wishlistID, Title: "BookTest", Author: "Anon"}, nil
+}
+func (m *mockBook) Update(ctx context.Context, userID, wishlistID, bookID uint, upd service.BookUpdate) (*service.Book, error) {
+	b, err := m.Get(ctx, userID, wishlistID, bookID)
+	if err != nil {
+		return nil, err
+	}
+	if upd.Title != nil {
+		b.Title = *upd.Title
+	}
+	if upd.Author != nil {
+		b.Author = *upd.Author
+	}
+	if upd.Authors != nil {
+		b.Authors, b.Author = *upd.Authors, ""
+		if len(b.Authors) > 0 {
+			b.Author = b.Authors[0]
+		}
+	}
+	if upd.PageCount != nil {
+		b.PageCount = *upd.PageCount
+	}
+	return b, nil
+}
+func (m *mockBook) List(ctx context.Context, userID, wishlistID uint, opts service.ListOptions) (*service.Page[service.Book], error) {
+	if err := m.checkOwner(userID, wishlistID); err != nil {
+		return nil, err
+	}
+	return &service.Page[service.Book]{Items: []service.Book{{ID: 1, WishlistID: wishlistID, Title: "BookTest", Author: "Anon"}}}, nil
+}
+func (m *mockBook) Delete(ctx context.Context, userID, wishlistID, bookID uint) error {
+	if err := m.checkOwner(userID, wishlistID); err != nil {
+		return err
+	}
+	if bookID == 999 {
+		return service.ErrBookNotFound
+	}
+	return nil
 }
-func (m *mockBook) Delete(wishlistID, bookID uint) error { return nil }
 
 //
 // ──────────────── HELPERS ────────────────
 //
 
+// mockEnrich is a mock EnrichmentUsecase with a single job "job1" that
+// enriches wishlist 1 on behalf of user 1.
+type mockEnrich struct{}
+
+func (m *mockEnrich) Enrich(ctx context.Context, userID, wishlistID uint) (*service.EnrichJob, error) {
+	if wishlistID != 1 {
+		return nil, service.ErrWishlistNotFound
+	}
+	return &service.EnrichJob{ID: "job1", UserID: userID, WishlistID: wishlistID, Status: service.JobRunning, Total: 3}, nil
+}
+
+func (m *mockEnrich) Job(ctx context.Context, userID uint, jobID string) (*service.EnrichJob, error) {
+	if userID != 1 || jobID != "job1" {
+		return nil, service.ErrEnrichJobNotFound
+	}
+	return &service.EnrichJob{
+		ID: "job1", UserID: 1, WishlistID: 1, Status: service.JobDone, Total: 3,
+		EnrichStats: service.EnrichStats{Processed: 3, Enriched: 2, Skipped: 1},
+	}, nil
+}
+
+// mockSearch is a mock WishlistSearchUsecase: user 1 has one book matching
+// any query, everyone else has none.
+type mockSearch struct{}
+
+func (m *mockSearch) Search(ctx context.Context, userID uint, query string, limit int) ([]service.BookHit, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, service.NewError(service.ErrValidation, "search query must contain at least one letter or digit")
+	}
+	if userID != 1 {
+		return nil, nil
+	}
+	return []service.BookHit{{
+		Book:       service.Book{ID: 4, WishlistID: 2, Title: "The Hobbit", Authors: []string{"J. R. R. Tolkien"}},
+		Wishlist:   service.Wishlist{ID: 2, UserID: 1, Name: "Fantasy"},
+		Score:      12.5,
+		Highlights: map[string]string{"authors": "J. R. R. <mark>Tolkien</mark>"},
+	}}, nil
+}
+
+// mockTrash is a mock TrashUsecase: user 1 has wishlist 3 and book 8 of
+// wishlist 1 in the trash. Ownership follows mockBook.
+type mockTrash struct{}
+
+// trashDeletedAt is when every item of mockTrash was deleted.
+var trashDeletedAt = time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+
+func (m *mockTrash) List(ctx context.Context, userID uint) (*service.Trash, error) {
+	if userID != 1 {
+		return &service.Trash{Retention: 24 * time.Hour}, nil
+	}
+	deleted := service.Timestamps{CreatedAt: trashDeletedAt.Add(-time.Hour), DeletedAt: gorm.DeletedAt{Time: trashDeletedAt, Valid: true}}
+	return &service.Trash{
+		Wishlists: []service.Wishlist{{ID: 3, UserID: 1, Name: "Old", Timestamps: deleted}},
+		Books:     []service.Book{{ID: 8, WishlistID: 1, Title: "Dune", Timestamps: deleted}},
+		Retention: 24 * time.Hour,
+	}, nil
+}
+func (m *mockTrash) RestoreWishlist(ctx context.Context, userID, wishlistID uint) (*service.Wishlist, error) {
+	if userID != 1 || wishlistID != 3 {
+		return nil, service.ErrWishlistNotFound
+	}
+	return &service.Wishlist{ID: 3, UserID: 1, Name: "Old"}, nil
+}
+func (m *mockTrash) RestoreBook(ctx context.Context, userID, wishlistID, bookID uint) (*service.Book, error) {
+	if err := (&mockBook{}).checkOwner(userID, wishlistID); err != nil {
+		return nil, err
+	}
+	if wishlistID != 1 || bookID != 8 {
+		return nil, service.ErrBookNotFound
+	}
+	return &service.Book{ID: 8, WishlistID: 1, Title: "Dune"}, nil
+}
+
+// testTokens is the JWT manager shared by the router and the tests.
+var testTokens, _ = auth.NewManager(auth.Config{SigningKey: []byte("test-secret")})
+
 // setupRouter builds a test HTTP router with mock services.
 // It registers the same routes as in main.go but uses mock implementations.
 func setupRouter() *mux.Router {
@@ -60,24 +274,62 @@ func setupRouter() *mux.Router {
 
 	mainHandler := NewHTTPHandler(wSvc, uSvc)
 	bookHandler := NewBookHTTP(bSvc)
+	authHandler := NewAuthHTTP(uSvc, testTokens)
+	enrichHandler := NewEnrichmentHTTP(&mockEnrich{})
+	searchHandler := NewWishlistSearchHTTP(&mockSearch{})
+	trashHandler := NewTrashHTTP(&mockTrash{})
 
 	r := mux.NewRouter()
 	api := r.PathPrefix("/api").Subrouter()
 
 	// Same routes defined in main.go
 	api.HandleFunc("/users/register", mainHandler.RegisterUser).Methods(http.MethodPost)
-	api.HandleFunc("/users", mainHandler.ListUsers).Methods(http.MethodGet)
-	api.HandleFunc("/wishlist", mainHandler.CreateWishlist).Methods(http.MethodPost)
-	api.HandleFunc("/wishlist", mainHandler.ListWishlists).Methods(http.MethodGet)
-	api.HandleFunc("/wishlist/{id}", mainHandler.DeleteWishlist).Methods(http.MethodDelete)
+	api.HandleFunc("/users/login", authHandler.Login).Methods(http.MethodPost)
+	api.HandleFunc("/isbn/{isbn}", bookHandler.CheckISBN).Methods(http.MethodGet)
+
+	secured := api.NewRoute().Subrouter()
+	secured.Use(auth.Middleware(testTokens))
+
+	admin := secured.NewRoute().Subrouter()
+	admin.Use(RequireAdmin(uSvc))
+	admin.HandleFunc("/users", mainHandler.ListUsers).Methods(http.MethodGet)
+
+	secured.HandleFunc("/users/me", mainHandler.Me).Methods(http.MethodGet)
+	secured.HandleFunc("/wishlist", mainHandler.CreateWishlist).Methods(http.MethodPost)
+	secured.HandleFunc("/wishlist", mainHandler.ListWishlists).Methods(http.MethodGet)
+	secured.HandleFunc("/wishlist/{id}", mainHandler.GetWishlist).Methods(http.MethodGet)
+	secured.HandleFunc("/wishlist/{id}", mainHandler.UpdateWishlist).Methods(http.MethodPut)
+	secured.HandleFunc("/wishlist/{id}", mainHandler.PatchWishlist).Methods(http.MethodPatch)
+	secured.HandleFunc("/wishlist/{id}", mainHandler.DeleteWishlist).Methods(http.MethodDelete)
 
-	api.HandleFunc("/wishlist/{id}/books", bookHandler.AddBook).Methods(http.MethodPost)
-	api.HandleFunc("/wishlist/{id}/books", bookHandler.ListBooks).Methods(http.MethodGet)
-	api.HandleFunc("/wishlist/{id}/books/{bookID}", bookHandler.DeleteBook).Methods(http.MethodDelete)
+	secured.HandleFunc("/wishlist/{id}/books", bookHandler.AddBook).Methods(http.MethodPost)
+	secured.HandleFunc("/wishlist/{id}/books", bookHandler.ListBooks).Methods(http.MethodGet)
+	secured.HandleFunc("/wishlist/{id}/books/from-google", bookHandler.AddFromGoogle).Methods(http.MethodPost)
+	secured.HandleFunc("/wishlist/{id}/duplicates", bookHandler.ListDuplicates).Methods(http.MethodGet)
+	secured.HandleFunc("/wishlist/{id}/books/enrich", enrichHandler.EnrichBooks).Methods(http.MethodPost)
+	secured.HandleFunc("/wishlist/{id}/books/enrich/{jobID}", enrichHandler.GetEnrichJob).Methods(http.MethodGet)
+	secured.HandleFunc("/wishlist/{id}/books/{bookID}", bookHandler.GetBook).Methods(http.MethodGet)
+	secured.HandleFunc("/wishlist/{id}/books/{bookID}", bookHandler.UpdateBook).Methods(http.MethodPut)
+	secured.HandleFunc("/wishlist/{id}/books/{bookID}", bookHandler.PatchBook).Methods(http.MethodPatch)
+	secured.HandleFunc("/wishlist/{id}/books/{bookID}", bookHandler.DeleteBook).Methods(http.MethodDelete)
+	secured.HandleFunc("/search", searchHandler.Search).Methods(http.MethodGet)
+	secured.HandleFunc("/trash", trashHandler.ListTrash).Methods(http.MethodGet)
+	secured.HandleFunc("/wishlist/{id}/restore", trashHandler.RestoreWishlist).Methods(http.MethodPost)
+	secured.HandleFunc("/wishlist/{id}/books/{bookID}/restore", trashHandler.RestoreBook).Methods(http.MethodPost)
 
 	return r
 }
 
+// authorize adds a valid bearer token for the given user to the request.
+func authorize(t *testing.T, req *http.Request, userID uint) {
+	t.Helper()
+	token, _, err := testTokens.Issue(userID, "david")
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
 //
 // ──────────────── TESTS ────────────────
 //
@@ -101,6 +353,7 @@ func TestEndpoints(t *testing.T) {
 	bodyWL, _ := json.Marshal(CreateWishlistRequest{Name: "MyList"})
 	req = httptest.NewRequest(http.MethodPost, "/api/wishlist", bytes.NewBuffer(bodyWL))
 	req.Header.Set("Content-Type", "application/json")
+	authorize(t, req, 1)
 	resp = httptest.NewRecorder()
 	router.ServeHTTP(resp, req)
 	if resp.Code != http.StatusCreated {
@@ -109,6 +362,7 @@ func TestEndpoints(t *testing.T) {
 
 	// LIST WISHLIST
 	req = httptest.NewRequest(http.MethodGet, "/api/wishlist", nil)
+	authorize(t, req, 1)
 	resp = httptest.NewRecorder()
 	router.ServeHTTP(resp, req)
 	if resp.Code != http.StatusOK {
@@ -119,6 +373,7 @@ func TestEndpoints(t *testing.T) {
 	bookPayload := `{"title":"Go 101","author":"Unknown"}`
 	req = httptest.NewRequest(http.MethodPost, "/api/wishlist/1/books", bytes.NewBufferString(bookPayload))
 	req.Header.Set("Content-Type", "application/json")
+	authorize(t, req, 1)
 	resp = httptest.NewRecorder()
 	router.ServeHTTP(resp, req)
 	if resp.Code != http.StatusCreated {
@@ -127,6 +382,7 @@ func TestEndpoints(t *testing.T) {
 
 	// LIST BOOKS
 	req = httptest.NewRequest(http.MethodGet, "/api/wishlist/1/books", nil)
+	authorize(t, req, 1)
 	resp = httptest.NewRecorder()
 	router.ServeHTTP(resp, req)
 	if resp.Code != http.StatusOK {
@@ -135,8 +391,958 @@ func TestEndpoints(t *testing.T) {
 
 	// LIST USERS
 	req = httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	authorize(t, req, 1)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.Code)
+	}
+}
+
+// TestLogin verifies that valid credentials return a usable bearer token
+// and invalid ones are rejected with 401.
+func TestLogin(t *testing.T) {
+	router := setupRouter()
+
+	// Valid credentials
+	body, _ := json.Marshal(LoginRequest{Username: "david", Password: "1234"})
+	req := httptest.NewRequest(http.MethodPost, "/api/users/login", bytes.NewBuffer(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	var out LoginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil || out.Token == "" {
+		t.Fatalf("expected token in response, got %+v (%v)", out, err)
+	}
+
+	// Token grants access to a protected route
+	req = httptest.NewRequest(http.MethodGet, "/api/wishlist", nil)
+	req.Header.Set("Authorization", "Bearer "+out.Token)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.Code)
+	}
+
+	// Wrong password
+	body, _ = json.Marshal(LoginRequest{Username: "david", Password: "wrong"})
+	req = httptest.NewRequest(http.MethodPost, "/api/users/login", bytes.NewBuffer(body))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", resp.Code)
+	}
+}
+
+// TestProtectedRoutes_RequireToken ensures protected routes reject requests
+// without a valid bearer token.
+func TestProtectedRoutes_RequireToken(t *testing.T) {
+	router := setupRouter()
+
+	for _, path := range []string{"/api/wishlist", "/api/wishlist/1/books", "/api/users"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401, got %d", path, resp.Code)
+		}
+
+		req = httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer not-a-token")
+		resp = httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 for bad token, got %d", path, resp.Code)
+		}
+	}
+}
+
+// TestUsers_AdminAndMe verifies that listing users is restricted to admins,
+// that /users/me returns the caller, and that password hashes never appear
+// in responses.
+func TestUsers_AdminAndMe(t *testing.T) {
+	router := setupRouter()
+
+	// Admin can list users
+	req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	authorize(t, req, 1)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.Code)
+	}
+	if bytes.Contains(resp.Body.Bytes(), []byte("assword")) {
+		t.Errorf("response leaks password field: %s", resp.Body.String())
+	}
+
+	// Regular user is forbidden
+	req = httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	authorize(t, req, 2)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", resp.Code)
+	}
+
+	// /users/me returns the caller
+	req = httptest.NewRequest(http.MethodGet, "/api/users/me", nil)
+	authorize(t, req, 2)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	var me UserResponse
+	if err := json.NewDecoder(resp.Body).Decode(&me); err != nil {
+		t.Fatal(err)
+	}
+	if me.ID != 2 || me.Username != "alice" || me.IsAdmin {
+		t.Errorf("unexpected user: %+v", me)
+	}
+}
+
+// pagedBook records the options of the last List call and always reports a next page.
+type pagedBook struct {
+	mockBook
+	opts service.ListOptions
+}
+
+func (m *pagedBook) List(ctx context.Context, userID, wishlistID uint, opts service.ListOptions) (*service.Page[service.Book], error) {
+	m.opts = opts
+	if opts.Sort == "price" {
+		return nil, service.NewError(service.ErrValidation, `cannot sort by "price"`)
+	}
+	return &service.Page[service.Book]{Items: []service.Book{{ID: 1, WishlistID: wishlistID, Title: "BookTest"}}, NextCursor: "abc"}, nil
+}
+
+// TestListBooks_Pagination verifies that list query parameters reach the
+// service and that the next page is advertised in the Link and X-Next-Cursor headers.
+func TestListBooks_Pagination(t *testing.T) {
+	book := &pagedBook{}
+	r := mux.NewRouter()
+	api := r.PathPrefix("/api").Subrouter()
+	api.Use(auth.Middleware(testTokens))
+	api.HandleFunc("/wishlist/{id}/books", NewBookHTTP(book).ListBooks).Methods(http.MethodGet)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/wishlist/1/books?limit=1&sort=-title&author=tolkien&title=ring&name=ignored", nil)
+	authorize(t, req, 1)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	want := service.ListOptions{Limit: 1, Sort: "title", Desc: true, Filters: map[string]string{"author": "tolkien", "title": "ring"}}
+	if !reflect.DeepEqual(book.opts, want) {
+		t.Errorf("unexpected options %+v", book.opts)
+	}
+	if got := resp.Header().Get("X-Next-Cursor"); got != "abc" {
+		t.Errorf("unexpected X-Next-Cursor %q", got)
+	}
+	link := resp.Header().Get("Link")
+	if !strings.HasPrefix(link, "</api/wishlist/1/books?") || !strings.HasSuffix(link, `>; rel="next"`) ||
+		!strings.Contains(link, "cursor=abc") || !strings.Contains(link, "sort=-title") || !strings.Contains(link, "limit=1") {
+		t.Errorf("unexpected Link header %q", link)
+	}
+	var books []BookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&books); err != nil || len(books) != 1 {
+		t.Errorf("expected a JSON array with one book, got %v (%v)", books, err)
+	}
+
+	for _, bad := range []string{"?limit=0", "?limit=x", "?sort=price"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/wishlist/1/books"+bad, nil)
+		authorize(t, req, 1)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		if resp.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", bad, resp.Code)
+		}
+	}
+
+	// The last page carries no next-page headers
+	req = httptest.NewRequest(http.MethodGet, "/api/wishlist/1/books", nil)
+	authorize(t, req, 1)
+	resp = httptest.NewRecorder()
+	setupRouter().ServeHTTP(resp, req)
+	if resp.Header().Get("Link") != "" || resp.Header().Get("X-Next-Cursor") != "" {
+		t.Errorf("unexpected next-page headers %v", resp.Header())
+	}
+}
+
+// TestBooks_Ownership verifies that book endpoints return 404 both for
+// wishlists owned by someone else and for wishlists that do not exist.
+func TestBooks_Ownership(t *testing.T) {
+	router := setupRouter()
+
+	cases := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodGet, "/api/wishlist/2/books", "", http.StatusNotFound},
+		{http.MethodPost, "/api/wishlist/2/books", `{"title":"Go 101","author":"Anon"}`, http.StatusNotFound},
+		{http.MethodDelete, "/api/wishlist/2/books/1", "", http.StatusNotFound},
+		{http.MethodGet, "/api/wishlist/999/books", "", http.StatusNotFound},
+		{http.MethodDelete, "/api/wishlist/1/books/1", "", http.StatusNoContent},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, bytes.NewBufferString(c.body))
+		authorize(t, req, 1)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != c.want {
+			t.Errorf("%s %s: expected %d, got %d", c.method, c.path, c.want, resp.Code)
+		}
+	}
+}
+
+// TestErrorResponses verifies that service errors are mapped to the right
+// status codes with a consistent JSON error body.
+func TestErrorResponses(t *testing.T) {
+	router := setupRouter()
+
+	cases := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		auth    bool
+		want    int
+		wantMsg string
+	}{
+		{"duplicate username", http.MethodPost, "/api/users/register", `{"username":"taken","password":"x"}`, false, http.StatusConflict, "username already exists"},
+		{"bad payload", http.MethodPost, "/api/wishlist", `{`, true, http.StatusBadRequest, "invalid payload"},
+		{"missing wishlist", http.MethodGet, "/api/wishlist/999/books", "", true, http.StatusNotFound, "wishlist not found"},
+		{"foreign wishlist", http.MethodGet, "/api/wishlist/2/books", "", true, http.StatusNotFound, "wishlist not found"},
+		{"no token", http.MethodGet, "/api/wishlist", "", false, http.StatusUnauthorized, ""},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, bytes.NewBufferString(c.body))
+		if c.auth {
+			authorize(t, req, 1)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		if resp.Code != c.want {
+			t.Errorf("%s: expected %d, got %d", c.name, c.want, resp.Code)
+		}
+		if ct := resp.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s: expected JSON content type, got %q", c.name, ct)
+		}
+		var body ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Errorf("%s: invalid JSON body: %v", c.name, err)
+			continue
+		}
+		if body.Status != c.want || (c.wantMsg != "" && body.Error != c.wantMsg) {
+			t.Errorf("%s: unexpected body %+v", c.name, body)
+		}
+	}
+}
+
+// TestDelete_NotFound verifies that deleting wishlists or books that do not
+// exist returns 404 instead of 204.
+func TestDelete_NotFound(t *testing.T) {
+	router := setupRouter()
+
+	cases := []struct {
+		path string
+		want int
+	}{
+		{"/api/wishlist/1", http.StatusNoContent},
+		{"/api/wishlist/999", http.StatusNotFound},
+		{"/api/wishlist/1/books/1", http.StatusNoContent},
+		{"/api/wishlist/1/books/999", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodDelete, c.path, nil)
+		authorize(t, req, 1)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != c.want {
+			t.Errorf("DELETE %s: expected %d, got %d", c.path, c.want, resp.Code)
+		}
+	}
+}
+
+// TestWishlistCRUD verifies create (201 + Location + body), get, put and patch.
+func TestWishlistCRUD(t *testing.T) {
+	router := setupRouter()
+
+	// CREATE returns the new wishlist and its location
+	req := httptest.NewRequest(http.MethodPost, "/api/wishlist", bytes.NewBufferString(`{"name":"MyList"}`))
+	authorize(t, req, 1)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.Code)
+	}
+	if loc := resp.Header().Get("Location"); loc != "/api/wishlist/7" {
+		t.Errorf("unexpected Location %q", loc)
+	}
+	var created WishlistResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil || created.ID != 7 || created.Name != "MyList" {
+		t.Errorf("unexpected body %+v (%v)", created, err)
+	}
+
+	cases := []struct {
+		method, path, body string
+		want               int
+		wantName           string
+	}{
+		{http.MethodGet, "/api/wishlist/1", "", http.StatusOK, "TestList"},
+		{http.MethodGet, "/api/wishlist/2", "", http.StatusNotFound, ""},
+		{http.MethodGet, "/api/wishlist/999", "", http.StatusNotFound, ""},
+		{http.MethodGet, "/api/wishlist/abc", "", http.StatusBadRequest, ""},
+		{http.MethodPut, "/api/wishlist/1", `{"name":"Renamed"}`, http.StatusOK, "Renamed"},
+		{http.MethodPut, "/api/wishlist/1", `{}`, http.StatusBadRequest, ""},
+		{http.MethodPatch, "/api/wishlist/1", `{}`, http.StatusOK, "TestList"},
+		{http.MethodPatch, "/api/wishlist/1", `{"name":"Patched"}`, http.StatusOK, "Patched"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, bytes.NewBufferString(c.body))
+		authorize(t, req, 1)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != c.want {
+			t.Errorf("%s %s %s: expected %d, got %d", c.method, c.path, c.body, c.want, resp.Code)
+			continue
+		}
+		if c.wantName != "" {
+			var out WishlistResponse
+			json.NewDecoder(resp.Body).Decode(&out)
+			if out.Name != c.wantName {
+				t.Errorf("%s %s: expected name %q, got %q", c.method, c.path, c.wantName, out.Name)
+			}
+		}
+	}
+}
+
+// TestBookCRUD verifies add (201 + Location + body), get, put and patch of
+// a single book, including that PATCH only touches supplied fields.
+func TestBookCRUD(t *testing.T) {
+	router := setupRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/wishlist/1/books", bytes.NewBufferString(`{"title":"Go 101","author":"Anon"}`))
+	authorize(t, req, 1)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.Code)
+	}
+	if loc := resp.Header().Get("Location"); loc != "/api/wishlist/1/books/3" {
+		t.Errorf("unexpected Location %q", loc)
+	}
+	var created BookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil || created.ID != 3 || created.Title != "Go 101" {
+		t.Errorf("unexpected body %+v (%v)", created, err)
+	}
+
+	cases := []struct {
+		method, path, body    string
+		want                  int
+		wantTitle, wantAuthor string
+	}{
+		{http.MethodGet, "/api/wishlist/1/books/5", "", http.StatusOK, "BookTest", "Anon"},
+		{http.MethodGet, "/api/wishlist/1/books/999", "", http.StatusNotFound, "", ""},
+		{http.MethodGet, "/api/wishlist/2/books/5", "", http.StatusNotFound, "", ""},
+		{http.MethodPut, "/api/wishlist/1/books/5", `{"title":"New","author":"Someone"}`, http.StatusOK, "New", "Someone"},
+		{http.MethodPatch, "/api/wishlist/1/books/5", `{"author":"Only Author"}`, http.StatusOK, "BookTest", "Only Author"},
+		{http.MethodPatch, "/api/wishlist/1/books/5", `{"title":"Only Title"}`, http.StatusOK, "Only Title", "Anon"},
+		{http.MethodPatch, "/api/wishlist/1/books/x", `{}`, http.StatusBadRequest, "", ""},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, bytes.NewBufferString(c.body))
+		authorize(t, req, 1)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != c.want {
+			t.Errorf("%s %s %s: expected %d, got %d", c.method, c.path, c.body, c.want, resp.Code)
+			continue
+		}
+		if c.want == http.StatusOK {
+			var out BookResponse
+			json.NewDecoder(resp.Body).Decode(&out)
+			if out.Title != c.wantTitle || out.Author != c.wantAuthor {
+				t.Errorf("%s %s %s: unexpected book %+v", c.method, c.path, c.body, out)
+			}
+		}
+	}
+}
+
+// TestBookUpdate_ClearAuthors verifies that PUT without author fields and
+// PATCH with an empty authors list both clear the authors.
+func TestBookUpdate_ClearAuthors(t *testing.T) {
+	router := setupRouter()
+
+	cases := []struct{ method, body string }{
+		{http.MethodPut, `{"title":"New"}`},
+		{http.MethodPut, `{"title":"New","author":""}`},
+		{http.MethodPatch, `{"authors":[]}`},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, "/api/wishlist/1/books/5", bytes.NewBufferString(c.body))
+		authorize(t, req, 1)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusOK {
+			t.Errorf("%s %s: expected 200, got %d", c.method, c.body, resp.Code)
+			continue
+		}
+		var out BookResponse
+		json.NewDecoder(resp.Body).Decode(&out)
+		if out.Author != "" || len(out.Authors) != 0 {
+			t.Errorf("%s %s: expected no authors, got %q %v", c.method, c.body, out.Author, out.Authors)
+		}
+	}
+}
+
+// TestBookMetadata verifies that rich metadata is accepted on add and
+// returned in the response, with lists always encoded as arrays.
+func TestBookMetadata(t *testing.T) {
+	router := setupRouter()
+
+	body := `{"title":"The Little Prince","authors":["Antoine de Saint-Exupéry","Richard Howard"],` +
+		`"isbn_13":"9780156012195","publisher":"Harcourt","published_date":"2000","page_count":96,` +
+		`"language":"en","categories":["Fiction"],"thumbnail_url":"https://example.com/c.jpg"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/wishlist/1/books", bytes.NewBufferString(body))
+	authorize(t, req, 1)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.Code)
+	}
+	var out BookResponse
+	json.NewDecoder(resp.Body).Decode(&out)
+	if len(out.Authors) != 2 || out.ISBN13 != "9780156012195" || out.PageCount != 96 ||
+		out.Publisher != "Harcourt" || len(out.Categories) != 1 || out.ThumbnailURL == "" {
+		t.Errorf("metadata not returned: %+v", out)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/wishlist/1/books/5", nil)
+	authorize(t, req, 1)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	var raw map[string]any
+	json.NewDecoder(resp.Body).Decode(&raw)
+	if _, ok := raw["authors"].([]any); !ok {
+		t.Errorf("expected authors to be an array, got %v", raw["authors"])
+	}
+	if _, ok := raw["categories"].([]any); !ok {
+		t.Errorf("expected categories to be an array, got %v", raw["categories"])
+	}
+}
+
+// TestCheckISBN verifies the public ISBN helper for valid, convertible and
+// malformed input.
+func TestCheckISBN(t *testing.T) {
+	router := setupRouter()
+
+	cases := []struct {
+		isbn           string
+		valid          bool
+		isbn10, isbn13 string
+	}{
+		{"0-306-40615-2", true, "0306406152", "9780306406157"},
+		{"978-0-306-40615-7", true, "0306406152", "9780306406157"},
+		{"9791234567896", true, "", "9791234567896"},
+		{"0306406153", false, "", ""},
+		{"abc", false, "", ""},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/api/isbn/"+c.isbn, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d", c.isbn, resp.Code)
+			continue
+		}
+		var out ISBNResponse
+		json.NewDecoder(resp.Body).Decode(&out)
+		if out.Valid != c.valid || out.ISBN10 != c.isbn10 || out.ISBN13 != c.isbn13 {
+			t.Errorf("%s: unexpected response %+v", c.isbn, out)
+		}
+		if !c.valid && out.Reason == "" {
+			t.Errorf("%s: expected a reason for invalid input", c.isbn)
+		}
+	}
+}
+
+// TestBookDuplicates verifies the on_duplicate policies on add and the
+// duplicates report.
+func TestBookDuplicates(t *testing.T) {
+	router := setupRouter()
+
+	cases := []struct {
+		query   string
+		want    int
+		wantLoc string
+	}{
+		{"", http.StatusConflict, ""},
+		{"?on_duplicate=reject", http.StatusConflict, ""},
+		{"?on_duplicate=merge", http.StatusOK, "/api/wishlist/1/books/5"},
+		{"?on_duplicate=allow", http.StatusCreated, "/api/wishlist/1/books/3"},
+		{"?on_duplicate=ignore", http.StatusBadRequest, ""},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/wishlist/1/books"+c.query, bytes.NewBufferString(`{"title":"Dup"}`))
+		authorize(t, req, 1)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != c.want {
+			t.Errorf("%q: expected %d, got %d", c.query, c.want, resp.Code)
+		}
+		if loc := resp.Header().Get("Location"); loc != c.wantLoc {
+			t.Errorf("%q: unexpected Location %q", c.query, loc)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/wishlist/1/duplicates", nil)
+	authorize(t, req, 1)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	var groups []DuplicateGroupResponse
+	json.NewDecoder(resp.Body).Decode(&groups)
+	if len(groups) != 1 || len(groups[0].Books) != 2 || groups[0].MatchedBy[0] != "isbn" {
+		t.Errorf("unexpected duplicates %+v", groups)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/wishlist/2/duplicates", nil)
+	authorize(t, req, 1)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.Code)
+	}
+}
+
+// mockGoogle is a mock GoogleBooksUsecase that returns err when set and
+// records the parameters of the last search. The volume "slow" is only
+// answered when the request context is done.
+type mockGoogle struct {
+	err  error
+	last service.SearchParams
+}
+
+func (m *mockGoogle) Search(ctx context.Context, p service.SearchParams) (*service.SearchResult, error) {
+	m.last = p
+	if m.err != nil {
+		return nil, m.err
+	}
+	if p.Query == "" && p.Title == "" {
+		return nil, service.NewError(service.ErrValidation, "search requires a query")
+	}
+	res := &service.SearchResult{TotalItems: 30, StartIndex: p.StartIndex, MaxResults: 10}
+	if p.Query == "many" {
+		res.Items = []service.GoogleBook{{Title: "Go"}}
+		res.HasMore, res.NextStartIndex = true, p.StartIndex+10
+	}
+	return res, nil
+}
+
+func (m *mockGoogle) GetVolume(ctx context.Context, volumeID string) (*service.GoogleBook, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if volumeID == "slow" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if volumeID != "vol1" {
+		return nil, service.ErrVolumeNotFound
+	}
+	return &service.GoogleBook{ID: "vol1", Title: "Go", Authors: []string{"A", "B"}}, nil
+}
+
+// newGoogleHandler builds a GoogleBooksHTTP whose catalogue only has g as Google Books.
+func newGoogleHandler(g service.GoogleBooksUsecase) *GoogleBooksHTTP {
+	return NewGoogleBooksHTTP(g, service.NewCatalogService("", service.CatalogProvider{Name: service.SourceGoogleBooks, Client: g}))
+}
+
+// TestSearchBooks_UpstreamErrors verifies the status codes used for
+// Google Books failures and that empty results encode as an array.
+func TestSearchBooks_UpstreamErrors(t *testing.T) {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{nil, http.StatusOK},
+		{service.NewError(service.ErrUpstreamQuota, "quota"), http.StatusTooManyRequests},
+		{service.NewError(service.ErrUpstreamBadRequest, "rejected"), http.StatusBadGateway},
+		{&service.Error{Kind: service.ErrUpstreamUnavailable, Msg: "down", Err: errors.New("dial tcp")}, http.StatusServiceUnavailable},
+		{context.Canceled, statusClientClosedRequest},
+	}
+	for _, c := range cases {
+		r := mux.NewRouter()
+		newGoogleHandler(&mockGoogle{err: c.err}).RegisterGoogleRoutes(r)
+
+		req := httptest.NewRequest(http.MethodGet, "/books/search?q=go", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		if resp.Code != c.want {
+			t.Errorf("%v: expected %d, got %d", c.err, c.want, resp.Code)
+		}
+		if c.err == nil && !strings.Contains(resp.Body.String(), `"items":[]`) {
+			t.Errorf("expected empty items array, got %s", resp.Body.String())
+		}
+	}
+}
+
+// TestSearchBooks_Params verifies that query parameters are passed through
+// and that the envelope links to the next page.
+func TestSearchBooks_Params(t *testing.T) {
+	google := &mockGoogle{}
+	r := mux.NewRouter()
+	newGoogleHandler(google).RegisterGoogleRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/books/search?q=many&title=go&author=pike&isbn=0306406152&subject=cs"+
+		"&startIndex=20&maxResults=10&orderBy=newest&printType=books&language=en", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	want := service.SearchParams{Query: "many", Title: "go", Author: "pike", ISBN: "0306406152", Subject: "cs",
+		StartIndex: 20, MaxResults: 10, OrderBy: "newest", PrintType: "books", Language: "en"}
+	if google.last != want {
+		t.Errorf("unexpected params %+v", google.last)
+	}
+	var out SearchResponse
+	json.NewDecoder(resp.Body).Decode(&out)
+	if !out.HasMore || out.NextStartIndex == nil || *out.NextStartIndex != 30 || out.TotalItems != 30 {
+		t.Errorf("unexpected envelope %+v", out)
+	}
+	if !strings.HasPrefix(out.Next, "/books/search?") || !strings.Contains(out.Next, "startIndex=30") || !strings.Contains(out.Next, "orderBy=newest") {
+		t.Errorf("unexpected next link %q", out.Next)
+	}
+
+	for _, bad := range []string{"?q=go&startIndex=x", "?q=go&maxResults=ten", ""} {
+		req := httptest.NewRequest(http.MethodGet, "/books/search"+bad, nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		if resp.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", bad, resp.Code)
+		}
+	}
+}
+
+// TestSearchBooks_Provider verifies the provider parameter and that failed
+// providers of an aggregate search are reported in the envelope.
+func TestSearchBooks_Provider(t *testing.T) {
+	google := &mockGoogle{}
+	library := &mockGoogle{err: service.NewError(service.ErrUpstreamUnavailable, "open library is unavailable")}
+	catalog := service.NewCatalogService("",
+		service.CatalogProvider{Name: service.SourceGoogleBooks, Client: google},
+		service.CatalogProvider{Name: service.SourceOpenLibrary, Client: library},
+	)
+	r := mux.NewRouter()
+	NewGoogleBooksHTTP(google, catalog).RegisterGoogleRoutes(r)
+
+	cases := []struct {
+		query    string
+		want     int
+		provider string
+	}{
+		{"?q=many", http.StatusOK, "google_books"},
+		{"?q=many&provider=aggregate", http.StatusOK, "aggregate"},
+		{"?q=many&provider=open_library", http.StatusServiceUnavailable, ""},
+		{"?q=many&provider=amazon", http.StatusBadRequest, ""},
+	}
+	for _, c := range cases {
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/books/search"+c.query, nil))
+		if resp.Code != c.want {
+			t.Errorf("%s: expected %d, got %d", c.query, c.want, resp.Code)
+			continue
+		}
+		if c.want != http.StatusOK {
+			continue
+		}
+		var out SearchResponse
+		json.NewDecoder(resp.Body).Decode(&out)
+		if out.Provider != c.provider {
+			t.Errorf("%s: expected provider %q, got %q", c.query, c.provider, out.Provider)
+		}
+		if c.provider == "aggregate" && out.ProviderErrors["open_library"] != "open library is unavailable" {
+			t.Errorf("%s: unexpected provider errors %v", c.query, out.ProviderErrors)
+		}
+		if c.provider == "aggregate" && !strings.Contains(out.Next, "provider=aggregate") {
+			t.Errorf("%s: next link %q must keep the provider", c.query, out.Next)
+		}
+	}
+}
+
+// TestGetVolume verifies the single-volume endpoint.
+func TestGetVolume(t *testing.T) {
+	r := mux.NewRouter()
+	newGoogleHandler(&mockGoogle{}).RegisterGoogleRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/books/volumes/vol1", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	var book VolumeResponse
+	json.NewDecoder(resp.Body).Decode(&book)
+	if book.ID != "vol1" || len(book.Authors) != 2 {
+		t.Errorf("unexpected volume %+v", book)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/books/volumes/missing", nil)
 	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.Code)
+	}
+}
+
+// TestCacheStats verifies that cache counters are only exposed to admins,
+// and that the endpoint reports 404 when the Google Books client is not cached.
+func TestCacheStats(t *testing.T) {
+	statsRouter := func(g service.GoogleBooksUsecase) *mux.Router {
+		r := mux.NewRouter()
+		admin := r.NewRoute().Subrouter()
+		admin.Use(auth.Middleware(testTokens), RequireAdmin(&mockUser{}))
+		admin.HandleFunc("/books/cache/stats", newGoogleHandler(g).CacheStats).Methods(http.MethodGet)
+		return r
+	}
+	get := func(r *mux.Router, userID uint) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/books/cache/stats", nil)
+		if userID != 0 {
+			authorize(t, req, userID)
+		}
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+
+	r := statsRouter(&mockGoogle{})
+	for userID, want := range map[uint]int{0: http.StatusUnauthorized, 2: http.StatusForbidden, 1: http.StatusNotFound} {
+		if resp := get(r, userID); resp.Code != want {
+			t.Errorf("user %d: expected %d, got %d", userID, want, resp.Code)
+		}
+	}
+
+	cache := service.NewCachedGoogleBooks(&mockGoogle{}, service.CacheOptions{})
+	cache.GetVolume(t.Context(), "vol1")
+	cache.GetVolume(t.Context(), "vol1")
+	resp := get(statsRouter(cache), 1)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	var stats CacheStatsResponse
+	json.NewDecoder(resp.Body).Decode(&stats)
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// TestAddFromGoogle verifies importing a Google Books volume into a wishlist.
+func TestAddFromGoogle(t *testing.T) {
+	router := setupRouter()
+
+	cases := []struct {
+		path, body string
+		want       int
+	}{
+		{"/api/wishlist/1/books/from-google", `{"volume_id":"vol1"}`, http.StatusCreated},
+		{"/api/wishlist/1/books/from-google", `{"volume_id":"missing"}`, http.StatusNotFound},
+		{"/api/wishlist/2/books/from-google", `{"volume_id":"vol1"}`, http.StatusNotFound},
+		{"/api/wishlist/1/books/from-google", `{`, http.StatusBadRequest},
+		{"/api/wishlist/1/books/from-google?on_duplicate=bogus", `{"volume_id":"vol1"}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, c.path, bytes.NewBufferString(c.body))
+		authorize(t, req, 1)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != c.want {
+			t.Errorf("%s %s: expected %d, got %d", c.path, c.body, c.want, resp.Code)
+			continue
+		}
+		if c.want == http.StatusCreated {
+			var out BookResponse
+			json.NewDecoder(resp.Body).Decode(&out)
+			if out.ExternalID != "vol1" || out.ExternalSource != "google_books" {
+				t.Errorf("unexpected book %+v", out)
+			}
+			if loc := resp.Header().Get("Location"); loc != "/api/wishlist/1/books/4" {
+				t.Errorf("unexpected Location %q", loc)
+			}
+		}
+	}
+}
+
+// TestEnrichBooks verifies starting an enrichment job and polling it.
+func TestEnrichBooks(t *testing.T) {
+	router := setupRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/wishlist/1/books/enrich", nil)
+	authorize(t, req, 1)
+	resp := httptest.NewRecorder()
 	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", resp.Code)
+	}
+	if loc := resp.Header().Get("Location"); loc != "/api/wishlist/1/books/enrich/job1" {
+		t.Errorf("unexpected Location %q", loc)
+	}
+
+	cases := []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodPost, "/api/wishlist/2/books/enrich", http.StatusNotFound},
+		{http.MethodGet, "/api/wishlist/1/books/enrich/job1", http.StatusOK},
+		{http.MethodGet, "/api/wishlist/2/books/enrich/job1", http.StatusNotFound},
+		{http.MethodGet, "/api/wishlist/1/books/enrich/other", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, nil)
+		authorize(t, req, 1)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != c.want {
+			t.Errorf("%s %s: expected %d, got %d", c.method, c.path, c.want, resp.Code)
+			continue
+		}
+		if c.want == http.StatusOK {
+			var out EnrichJobResponse
+			json.NewDecoder(resp.Body).Decode(&out)
+			if out.Status != "done" || out.Processed != 3 || out.Enriched != 2 {
+				t.Errorf("unexpected job %+v", out)
+			}
+		}
+	}
+}
+
+// TestSearchWishlists verifies that hits carry the book, its wishlist and the
+// highlights, and that bad queries are rejected.
+func TestSearchWishlists(t *testing.T) {
+	router := setupRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=tolkien", nil)
+	authorize(t, req, 1)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	var hits []SearchHitResponse
+	if err := json.NewDecoder(resp.Body).Decode(&hits); err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].Book.Title != "The Hobbit" || hits[0].Wishlist.Name != "Fantasy" ||
+		hits[0].Highlights["authors"] != "J. R. R. <mark>Tolkien</mark>" {
+		t.Errorf("unexpected hits %+v", hits)
+	}
+
+	// No hits is an empty array
+	req = httptest.NewRequest(http.MethodGet, "/api/search?q=tolkien", nil)
+	authorize(t, req, 2)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if strings.TrimSpace(resp.Body.String()) != "[]" {
+		t.Errorf("expected an empty array, got %s", resp.Body.String())
+	}
+
+	for _, bad := range []string{"", "?q=", "?q=go&limit=0", "?q=go&limit=x"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/search"+bad, nil)
+		authorize(t, req, 1)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", bad, resp.Code)
+		}
+	}
+}
+
+// TestTrash verifies the trash listing, with purge times, and the restore endpoints.
+func TestTrash(t *testing.T) {
+	router := setupRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/trash", nil)
+	authorize(t, req, 1)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	var trash TrashResponse
+	if err := json.NewDecoder(resp.Body).Decode(&trash); err != nil {
+		t.Fatal(err)
+	}
+	purgeAt := trashDeletedAt.Add(24 * time.Hour)
+	if len(trash.Wishlists) != 1 || trash.Wishlists[0].Name != "Old" ||
+		!trash.Wishlists[0].DeletedAt.Equal(trashDeletedAt) || !trash.Wishlists[0].PurgeAt.Equal(purgeAt) {
+		t.Errorf("unexpected wishlists %+v", trash.Wishlists)
+	}
+	if len(trash.Books) != 1 || trash.Books[0].Title != "Dune" || !trash.Books[0].PurgeAt.Equal(purgeAt) ||
+		trash.Books[0].CreatedAt == nil || trash.Books[0].UpdatedAt != nil {
+		t.Errorf("unexpected books %+v", trash.Books)
+	}
+
+	// An empty trash has empty arrays
+	req = httptest.NewRequest(http.MethodGet, "/api/trash", nil)
+	authorize(t, req, 2)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if body := strings.TrimSpace(resp.Body.String()); body != `{"wishlists":[],"books":[]}` {
+		t.Errorf("unexpected empty trash %s", body)
+	}
+
+	tests := []struct {
+		path   string
+		userID uint
+		want   int
+	}{
+		{"/api/wishlist/3/restore", 1, http.StatusOK},
+		{"/api/wishlist/3/restore", 2, http.StatusNotFound},
+		{"/api/wishlist/x/restore", 1, http.StatusBadRequest},
+		{"/api/wishlist/1/books/8/restore", 1, http.StatusOK},
+		{"/api/wishlist/1/books/9/restore", 1, http.StatusNotFound},
+		{"/api/wishlist/1/books/8/restore", 2, http.StatusNotFound},
+		{"/api/wishlist/9/books/8/restore", 1, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+		authorize(t, req, tt.userID)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != tt.want {
+			t.Errorf("%s as user %d: expected %d, got %d", tt.path, tt.userID, tt.want, resp.Code)
+		}
+	}
+}
+
+// TestRequestTimeout verifies that the Timeout middleware cancels slow work
+// and that the client receives 504 Gateway Timeout.
+func TestRequestTimeout(t *testing.T) {
+	r := mux.NewRouter()
+	r.Use(Timeout(20 * time.Millisecond))
+	newGoogleHandler(&mockGoogle{}).RegisterGoogleRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/books/volumes/slow", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	if resp.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d", resp.Code)
+	}
+	var body ErrorResponse
+	json.NewDecoder(resp.Body).Decode(&body)
+	if body.Error != "request timed out" {
+		t.Errorf("unexpected error %q", body.Error)
+	}
+
+	// Fast requests are unaffected
+	req = httptest.NewRequest(http.MethodGet, "/books/volumes/vol1", nil)
+	resp = httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
 	if resp.Code != http.StatusOK {
 		t.Errorf("expected 200, got %d", resp.Code)
 	}
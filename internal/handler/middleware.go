@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+)
+
+//
+// ─────────────────────────── MIDDLEWARE ───────────────────────────
+//
+
+// DefaultRequestTimeout is the deadline applied to each API request when
+// REQUEST_TIMEOUT is not set.
+const DefaultRequestTimeout = 30 * time.Second
+
+// Timeout gives every request a deadline of d. The deadline travels with the
+// request context into the services, so database queries and upstream calls
+// still running when it expires are abandoned and the client receives
+// 504 Gateway Timeout. A non-positive d leaves requests without a deadline.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireAdmin only lets requests from admin users through; others receive
+// 403 Forbidden. It must run after auth.Middleware, which identifies the
+// caller; callers whose account no longer exists receive 401 Unauthorized.
+func RequireAdmin(users service.UserUsecase) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := userIDFromRequest(w, r)
+			if !ok {
+				return
+			}
+			caller, err := users.Get(r.Context(), userID)
+			if errors.Is(err, service.ErrUserNotFound) {
+				writeError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+			if err != nil {
+				writeServiceError(w, err)
+				return
+			}
+			if !caller.IsAdmin {
+				writeError(w, http.StatusForbidden, "admin privileges required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
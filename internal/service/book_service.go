@@ -1,5 +1,14 @@
 package service
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
 //
 // ─────────────────────────── SERVICE IMPLEMENTATION ───────────────────────────
 //
@@ -7,26 +16,278 @@ package service
 // bookService implements the BookUsecase interface.
 // It contains the business logic for managing books inside wishlists.
 type bookService struct {
-	repo BookRepository
+	repo      BookRepository
+	wishlists WishlistRepository
+	google    GoogleBooksUsecase
+}
+
+// NewBookService creates a new instance of bookService with the provided repositories.
+// The wishlist repository is used to verify ownership before touching books,
+// and g is used to import books from Google Books (may be nil if unused).
+func NewBookService(r BookRepository, w WishlistRepository, g GoogleBooksUsecase) BookUsecase {
+	return &bookService{repo: r, wishlists: w, google: g}
+}
+
+// Add normalizes and stores a new book in the given wishlist and returns it with its ID.
+// Any ID or WishlistID set on book is ignored.
+//
+// If the wishlist already holds the same book (see matchBooks), policy decides
+// the outcome: DuplicateReject returns ErrDuplicateBook, DuplicateMerge fills
+// the stored book's empty fields and returns it with created=false, and
+// DuplicateAllow inserts the copy anyway.
+// Returns an ErrValidation error if the title is blank or the metadata is invalid.
+func (s *bookService) Add(ctx context.Context, userID, wishlistID uint, book Book, policy DuplicatePolicy) (*Book, bool, error) {
+	if err := normalizeBook(&book); err != nil {
+		return nil, false, err
+	}
+	if err := s.checkOwner(ctx, userID, wishlistID); err != nil {
+		return nil, false, err
+	}
+	book.ID, book.WishlistID = 0, wishlistID
+
+	if policy != DuplicateAllow {
+		existing, err := s.repo.List(ctx, wishlistID)
+		if err != nil {
+			return nil, false, err
+		}
+		if dup, reason := findDuplicate(existing, &book); dup != nil {
+			if policy != DuplicateMerge {
+				return nil, false, fmt.Errorf("%w (book %d, matched by %s)", ErrDuplicateBook, dup.ID, reason)
+			}
+			mergeBook(dup, book)
+			if err := s.repo.Update(ctx, dup); err != nil {
+				return nil, false, err
+			}
+			return dup, false, nil
+		}
+	}
+
+	if err := s.repo.Add(ctx, &book); err != nil {
+		return nil, false, err
+	}
+	return &book, true, nil
+}
+
+// AddFromGoogle fetches a Google Books volume and adds it to the wishlist
+// with its full metadata, following the same duplicate policy as Add.
+// Returns ErrVolumeNotFound if Google does not know the volume.
+func (s *bookService) AddFromGoogle(ctx context.Context, userID, wishlistID uint, volumeID string, policy DuplicatePolicy) (*Book, bool, error) {
+	if s.google == nil {
+		return nil, false, errors.New("google books client not configured")
+	}
+	// Check ownership first so that forbidden requests never reach Google.
+	if err := s.checkOwner(ctx, userID, wishlistID); err != nil {
+		return nil, false, err
+	}
+	volume, err := s.google.GetVolume(ctx, volumeID)
+	if err != nil {
+		return nil, false, err
+	}
+	return s.Add(ctx, userID, wishlistID, volume.ToBook(), policy)
+}
+
+// Duplicates lists groups of books in a wishlist that look like the same book.
+// Books match on ISBN when both have one, otherwise on title and primary
+// author ignoring case, accents and punctuation.
+func (s *bookService) Duplicates(ctx context.Context, userID, wishlistID uint) ([]DuplicateGroup, error) {
+	if err := s.checkOwner(ctx, userID, wishlistID); err != nil {
+		return nil, err
+	}
+	books, err := s.repo.List(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	return groupDuplicates(books), nil
+}
+
+// List retrieves one page of the books in a wishlist owned by the user,
+// sorted by "created", "title" or "author" and filtered by "title" or
+// "author" (which also matches co-authors).
+// Returns an ErrValidation error if opts is invalid.
+func (s *bookService) List(ctx context.Context, userID, wishlistID uint, opts ListOptions) (*Page[Book], error) {
+	if err := opts.normalize(bookListFields); err != nil {
+		return nil, err
+	}
+	if err := s.checkOwner(ctx, userID, wishlistID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPage(ctx, wishlistID, opts)
+}
+
+// Get retrieves a single book from a wishlist owned by the user.
+// Returns ErrBookNotFound if the wishlist has no book with that ID.
+func (s *bookService) Get(ctx context.Context, userID, wishlistID, bookID uint) (*Book, error) {
+	if err := s.checkOwner(ctx, userID, wishlistID); err != nil {
+		return nil, err
+	}
+	book, err := s.repo.Get(ctx, wishlistID, bookID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrBookNotFound
+	}
+	return book, err
+}
+
+// Update applies the non-nil fields of upd to a book and returns the result.
+// Fields that are not supplied keep their stored values.
+func (s *bookService) Update(ctx context.Context, userID, wishlistID, bookID uint, upd BookUpdate) (*Book, error) {
+	book, err := s.Get(ctx, userID, wishlistID, bookID)
+	if err != nil {
+		return nil, err
+	}
+	applyBookUpdate(book, upd)
+	if err := normalizeBook(book); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, book); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, err
+	}
+	return book, nil
+}
+
+// Delete moves a book to the trash using its wishlist ID and book ID (see TrashService).
+// Returns ErrBookNotFound if the wishlist has no book with that ID.
+func (s *bookService) Delete(ctx context.Context, userID, wishlistID, bookID uint) error {
+	if err := s.checkOwner(ctx, userID, wishlistID); err != nil {
+		return err
+	}
+	err := s.repo.Delete(ctx, wishlistID, bookID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrBookNotFound
+	}
+	return err
+}
+
+// checkOwner ensures the wishlist exists and belongs to the user.
+// Returns ErrWishlistNotFound otherwise.
+func (s *bookService) checkOwner(ctx context.Context, userID, wishlistID uint) error {
+	_, err := getOwnedWishlist(ctx, s.wishlists, userID, wishlistID)
+	return err
+}
+
+//
+// ─────────────────────────── METADATA HELPERS ───────────────────────────
+//
+
+// applyBookUpdate copies the non-nil fields of upd onto book.
+// A new primary author replaces the first entry of Authors unless the
+// full list is supplied as well; an empty list clears every author.
+func applyBookUpdate(book *Book, upd BookUpdate) {
+	if upd.Title != nil {
+		book.Title = *upd.Title
+	}
+	if upd.Author != nil && upd.Authors == nil {
+		if len(book.Authors) > 0 {
+			book.Authors = append([]string{*upd.Author}, book.Authors[1:]...)
+		}
+		book.Author = *upd.Author
+	}
+	if upd.Authors != nil {
+		// normalizeBook would otherwise restore the old Author into an empty list
+		book.Authors, book.Author = *upd.Authors, ""
+	}
+	// Changing one ISBN form invalidates the other; normalizeBook re-derives it.
+	if upd.ISBN10 != nil && upd.ISBN13 == nil {
+		book.ISBN10, book.ISBN13 = *upd.ISBN10, ""
+	}
+	if upd.ISBN13 != nil && upd.ISBN10 == nil {
+		book.ISBN10, book.ISBN13 = "", *upd.ISBN13
+	}
+	if upd.ISBN10 != nil && upd.ISBN13 != nil {
+		book.ISBN10, book.ISBN13 = *upd.ISBN10, *upd.ISBN13
+	}
+	setIfNotNil(&book.Publisher, upd.Publisher)
+	setIfNotNil(&book.PublishedDate, upd.PublishedDate)
+	setIfNotNil(&book.PageCount, upd.PageCount)
+	setIfNotNil(&book.Language, upd.Language)
+	setIfNotNil(&book.Categories, upd.Categories)
+	setIfNotNil(&book.Description, upd.Description)
+	setIfNotNil(&book.ThumbnailURL, upd.ThumbnailURL)
+	setIfNotNil(&book.Notes, upd.Notes)
+}
+
+// setIfNotNil assigns *src to *dst when src is non-nil.
+func setIfNotNil[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// normalizeBook trims every text field, validates the metadata and keeps
+// Author in sync with the first entry of Authors. ISBNs are stored without
+// hyphens, and the missing ISBN form is derived from the one supplied.
+func normalizeBook(b *Book) error {
+	var err error
+	if b.Title, err = validateBookTitle(b.Title); err != nil {
+		return err
+	}
+	b.Authors = cleanList(b.Authors)
+	if author := strings.TrimSpace(b.Author); len(b.Authors) == 0 && author != "" {
+		b.Authors = []string{author}
+	}
+	b.Author = ""
+	if len(b.Authors) > 0 {
+		b.Author = b.Authors[0]
+	}
+	b.Categories = cleanList(b.Categories)
+
+	b.Publisher = strings.TrimSpace(b.Publisher)
+	b.PublishedDate = strings.TrimSpace(b.PublishedDate)
+	b.Language = strings.ToLower(strings.TrimSpace(b.Language))
+	b.Description = strings.TrimSpace(b.Description)
+	b.ThumbnailURL = strings.TrimSpace(b.ThumbnailURL)
+	b.Notes = strings.TrimSpace(b.Notes)
+
+	if err := normalizeBookISBNs(b); err != nil {
+		return err
+	}
+	if b.PageCount < 0 {
+		return validationErrorf("page count cannot be negative")
+	}
+	if b.PublishedDate != "" && !validPublishedDate(b.PublishedDate) {
+		return validationErrorf("published date must be YYYY, YYYY-MM or YYYY-MM-DD")
+	}
+	if b.ThumbnailURL != "" && !validHTTPURL(b.ThumbnailURL) {
+		return validationErrorf("thumbnail URL must be an absolute http(s) URL")
+	}
+	return nil
 }
 
-// NewBookService creates a new instance of bookService with the provided repository.
-func NewBookService(r BookRepository) BookUsecase {
-	return &bookService{repo: r}
+// validateBookTitle trims the title and rejects blank values.
+func validateBookTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "", validationErrorf("book title cannot be empty")
+	}
+	return title, nil
 }
 
-// Add creates and stores a new book in the given wishlist.
-func (s *bookService) Add(wishlistID uint, title, author string) error {
-	book := Book{WishlistID: wishlistID, Title: title, Author: author}
-	return s.repo.Add(&book)
+// cleanList trims each entry and drops blanks, returning nil when empty.
+func cleanList(in []string) []string {
+	var out []string
+	for _, v := range in {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
 }
 
-// List retrieves all books associated with a given wishlist ID.
-func (s *bookService) List(wishlistID uint) ([]Book, error) {
-	return s.repo.List(wishlistID)
+// validPublishedDate reports whether s is a full or partial ISO date,
+// which is how catalogues such as Google Books publish them.
+func validPublishedDate(s string) bool {
+	for _, layout := range []string{"2006", "2006-01", "2006-01-02"} {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
 }
 
-// Delete removes a book from the repository using its wishlist ID and book ID.
-func (s *bookService) Delete(wishlistID, bookID uint) error {
-	return s.repo.Delete(wishlistID, bookID)
+// validHTTPURL reports whether s is an absolute http or https URL.
+func validHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
 }
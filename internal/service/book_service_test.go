@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -17,23 +20,28 @@ type mockBookRepo struct {
 	addCalled    bool
 	listCalled   bool
 	deleteCalled bool
+	listOpts     ListOptions // Options of the last ListPage call
+	searchTerms  []string    // Terms of the last Search call
+	searchLimit  int         // Limit of the last Search call
+	purgeBefore  time.Time   // Cutoff of the last Purge call
 	books        []Book
+	deleted      []Book // Books moved to the trash by Delete
 	err          error
 }
 
 // Add simulates inserting a book into the repository.
-func (m *mockBookRepo) Add(book *Book) error {
+func (m *mockBookRepo) Add(ctx context.Context, book *Book) error {
 	m.addCalled = true
 	if m.err != nil {
 		return m.err
 	}
-	book.ID = 1
+	book.ID = uint(len(m.books) + 1)
 	m.books = append(m.books, *book)
 	return nil
 }
 
 // List simulates fetching all books by wishlist ID.
-func (m *mockBookRepo) List(wishlistID uint) ([]Book, error) {
+func (m *mockBookRepo) List(ctx context.Context, wishlistID uint) ([]Book, error) {
 	m.listCalled = true
 	if m.err != nil {
 		return nil, m.err
@@ -41,8 +49,74 @@ func (m *mockBookRepo) List(wishlistID uint) ([]Book, error) {
 	return m.books, nil
 }
 
-// Delete simulates removing a book by wishlist ID and book ID.
-func (m *mockBookRepo) Delete(wishlistID, bookID uint) error {
+// ListPage simulates fetching a page of books; every book fits on one page.
+func (m *mockBookRepo) ListPage(ctx context.Context, wishlistID uint, opts ListOptions) (*Page[Book], error) {
+	m.listCalled, m.listOpts = true, opts
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &Page[Book]{Items: m.books}, nil
+}
+
+// Search simulates a full-text search; every stored book is a hit.
+func (m *mockBookRepo) Search(ctx context.Context, userID uint, terms []string, limit int) ([]BookHit, error) {
+	m.searchTerms, m.searchLimit = terms, limit
+	if m.err != nil {
+		return nil, m.err
+	}
+	hits := make([]BookHit, len(m.books))
+	for i, b := range m.books {
+		hits[i] = BookHit{Book: b, Wishlist: Wishlist{ID: b.WishlistID, UserID: userID}}
+	}
+	return hits, nil
+}
+
+// Get simulates fetching a single book by wishlist ID and book ID.
+func (m *mockBookRepo) Get(ctx context.Context, wishlistID, bookID uint) (*Book, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, b := range m.books {
+		if b.ID == bookID && b.WishlistID == wishlistID {
+			return &b, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Update simulates persisting a modified book.
+func (m *mockBookRepo) Update(ctx context.Context, book *Book) error {
+	if m.err != nil {
+		return m.err
+	}
+	for i, b := range m.books {
+		if b.ID == book.ID && b.WishlistID == book.WishlistID {
+			m.books[i] = *book
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// UpdateFields simulates persisting only some fields of a book.
+func (m *mockBookRepo) UpdateFields(ctx context.Context, book *Book, fields ...string) error {
+	if m.err != nil {
+		return m.err
+	}
+	for i, b := range m.books {
+		if b.ID == book.ID && b.WishlistID == book.WishlistID {
+			dst, src := reflect.ValueOf(&m.books[i]).Elem(), reflect.ValueOf(book).Elem()
+			for _, f := range fields {
+				dst.FieldByName(f).Set(src.FieldByName(f))
+			}
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// Delete simulates moving a book to the trash by wishlist ID and book ID.
+func (m *mockBookRepo) Delete(ctx context.Context, wishlistID, bookID uint) error {
 	m.deleteCalled = true
 	if m.err != nil {
 		return m.err
@@ -50,10 +124,125 @@ func (m *mockBookRepo) Delete(wishlistID, bookID uint) error {
 	for i, b := range m.books {
 		if b.ID == bookID && b.WishlistID == wishlistID {
 			m.books = append(m.books[:i], m.books[i+1:]...)
+			m.deleted = append(m.deleted, b)
 			return nil
 		}
 	}
-	return errors.New("book not found")
+	return ErrNotFound
+}
+
+// ListDeleted simulates fetching the trash; every deleted book belongs to the user.
+func (m *mockBookRepo) ListDeleted(ctx context.Context, userID uint) ([]Book, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.deleted, nil
+}
+
+// Restore simulates moving a book out of the trash.
+func (m *mockBookRepo) Restore(ctx context.Context, wishlistID, bookID uint) error {
+	if m.err != nil {
+		return m.err
+	}
+	for i, b := range m.deleted {
+		if b.ID == bookID && b.WishlistID == wishlistID {
+			m.deleted = append(m.deleted[:i], m.deleted[i+1:]...)
+			m.books = append(m.books, b)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// Purge simulates emptying the trash, whatever the cutoff.
+func (m *mockBookRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	m.purgeBefore = before
+	if m.err != nil {
+		return 0, m.err
+	}
+	n := int64(len(m.deleted))
+	m.deleted = nil
+	return n, nil
+}
+
+// ListMissingMetadata simulates fetching books that were never enriched.
+func (m *mockBookRepo) ListMissingMetadata(ctx context.Context, limit int) ([]Book, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var out []Book
+	for _, b := range m.books {
+		if b.EnrichedAt == nil && needsMetadata(&b) && len(out) < limit {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+// mockWishlistOwner simulates the Wishlist repository so services can check
+// ownership. Get and the trash methods are relevant; the others are no-ops.
+type mockWishlistOwner struct {
+	wishlists map[uint]Wishlist
+	deleted   map[uint]Wishlist // Wishlists in the trash
+	err       error
+}
+
+func (m *mockWishlistOwner) Add(ctx context.Context, w *Wishlist) error { return nil }
+func (m *mockWishlistOwner) ListPage(ctx context.Context, userID uint, opts ListOptions) (*Page[Wishlist], error) {
+	return &Page[Wishlist]{}, nil
+}
+func (m *mockWishlistOwner) Update(ctx context.Context, w *Wishlist) error             { return nil }
+func (m *mockWishlistOwner) Delete(ctx context.Context, userID, wishlistID uint) error { return nil }
+func (m *mockWishlistOwner) Purge(ctx context.Context, before time.Time) (int64, error) {
+	n := int64(len(m.deleted))
+	m.deleted = nil
+	return n, m.err
+}
+
+// ListDeleted returns the user's wishlists in the trash.
+func (m *mockWishlistOwner) ListDeleted(ctx context.Context, userID uint) ([]Wishlist, error) {
+	var out []Wishlist
+	for _, w := range m.deleted {
+		if w.UserID == userID {
+			out = append(out, w)
+		}
+	}
+	return out, m.err
+}
+
+// Restore moves a wishlist of the user out of the trash, or returns ErrNotFound.
+func (m *mockWishlistOwner) Restore(ctx context.Context, userID, wishlistID uint) error {
+	if m.err != nil {
+		return m.err
+	}
+	w, ok := m.deleted[wishlistID]
+	if !ok || w.UserID != userID {
+		return ErrNotFound
+	}
+	delete(m.deleted, wishlistID)
+	m.wishlists[wishlistID] = w
+	return nil
+}
+
+// Get returns the configured wishlist, or ErrNotFound if it does not exist.
+func (m *mockWishlistOwner) Get(ctx context.Context, wishlistID uint) (*Wishlist, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	w, ok := m.wishlists[wishlistID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &w, nil
+}
+
+// newOwner returns a mockWishlistOwner where wishlist 1 belongs to user 1
+// and wishlist 2 belongs to user 2.
+func newOwner() *mockWishlistOwner {
+	return &mockWishlistOwner{wishlists: map[uint]Wishlist{
+		1: {ID: 1, UserID: 1, Name: "Mine"},
+		2: {ID: 2, UserID: 2, Name: "Theirs"},
+	}}
 }
 
 //
@@ -64,10 +253,11 @@ func (m *mockBookRepo) Delete(wishlistID, bookID uint) error {
 // and verifies repository interaction.
 func TestBookService_Add(t *testing.T) {
 	mockRepo := &mockBookRepo{}
-	svc := NewBookService(mockRepo)
+	svc := NewBookService(mockRepo, newOwner(), nil)
 
-	err := svc.Add(1, "Go Programming", "Alice")
+	book, _, err := svc.Add(t.Context(), 1, 1, Book{Title: "Go Programming", Author: "Alice"}, DuplicateReject)
 	assert.NoError(t, err)
+	assert.Equal(t, uint(1), book.ID)
 	assert.True(t, mockRepo.addCalled)
 	assert.Len(t, mockRepo.books, 1)
 	assert.Equal(t, "Go Programming", mockRepo.books[0].Title)
@@ -79,13 +269,22 @@ func TestBookService_List(t *testing.T) {
 	mockRepo := &mockBookRepo{
 		books: []Book{{ID: 1, WishlistID: 1, Title: "Go 101", Author: "Bob"}},
 	}
-	svc := NewBookService(mockRepo)
+	svc := NewBookService(mockRepo, newOwner(), nil)
 
-	books, err := svc.List(1)
+	page, err := svc.List(t.Context(), 1, 1, ListOptions{Sort: "Title", Desc: true, Filters: map[string]string{"author": " bob "}})
 	assert.NoError(t, err)
 	assert.True(t, mockRepo.listCalled)
-	assert.Len(t, books, 1)
-	assert.Equal(t, "Go 101", books[0].Title)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "Go 101", page.Items[0].Title)
+	assert.Equal(t, ListOptions{Limit: DefaultPageLimit, Sort: "title", Desc: true, Filters: map[string]string{"author": "bob"}}, mockRepo.listOpts)
+
+	// Wishlist fields are not book fields
+	mockRepo.listCalled = false
+	_, err = svc.List(t.Context(), 1, 1, ListOptions{Sort: "name"})
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = svc.List(t.Context(), 1, 1, ListOptions{Filters: map[string]string{"name": "x"}})
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.False(t, mockRepo.listCalled)
 }
 
 // TestBookService_Delete ensures that a book can be deleted
@@ -94,10 +293,332 @@ func TestBookService_Delete(t *testing.T) {
 	mockRepo := &mockBookRepo{
 		books: []Book{{ID: 1, WishlistID: 1, Title: "Go 101", Author: "Bob"}},
 	}
-	svc := NewBookService(mockRepo)
+	svc := NewBookService(mockRepo, newOwner(), nil)
 
-	err := svc.Delete(1, 1)
+	err := svc.Delete(t.Context(), 1, 1, 1)
 	assert.NoError(t, err)
 	assert.True(t, mockRepo.deleteCalled)
 	assert.Len(t, mockRepo.books, 0)
 }
+
+// TestBookService_Ownership ensures that operations on wishlists owned by
+// another user, or on missing wishlists, never reach the book repository.
+func TestBookService_Ownership(t *testing.T) {
+	mockRepo := &mockBookRepo{
+		books: []Book{{ID: 1, WishlistID: 2, Title: "Go 101", Author: "Bob"}},
+	}
+	svc := NewBookService(mockRepo, newOwner(), nil)
+
+	_, _, err := svc.Add(t.Context(), 1, 2, Book{Title: "Go Programming", Author: "Alice"}, DuplicateReject)
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+
+	_, err = svc.List(t.Context(), 1, 2, ListOptions{})
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+
+	err = svc.Delete(t.Context(), 1, 2, 1)
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+
+	_, err = svc.List(t.Context(), 1, 999, ListOptions{})
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, _, err = svc.Add(t.Context(), 1, 1, Book{Title: "  ", Author: "Alice"}, DuplicateReject)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	assert.False(t, mockRepo.addCalled)
+	assert.False(t, mockRepo.listCalled)
+	assert.False(t, mockRepo.deleteCalled)
+	assert.Len(t, mockRepo.books, 1)
+}
+
+// TestBookService_DeleteNotFound ensures deleting a missing book reports ErrBookNotFound.
+func TestBookService_DeleteNotFound(t *testing.T) {
+	mockRepo := &mockBookRepo{}
+	svc := NewBookService(mockRepo, newOwner(), nil)
+
+	err := svc.Delete(t.Context(), 1, 1, 999)
+	assert.ErrorIs(t, err, ErrBookNotFound)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestBookService_RepoError ensures unexpected repository errors are propagated as-is.
+func TestBookService_RepoError(t *testing.T) {
+	mockRepo := &mockBookRepo{err: errors.New("db error")}
+	svc := NewBookService(mockRepo, newOwner(), nil)
+
+	err := svc.Delete(t.Context(), 1, 1, 1)
+	assert.EqualError(t, err, "db error")
+}
+
+// TestBookService_GetUpdate verifies fetching a book and partial updates that
+// only change supplied fields.
+func TestBookService_GetUpdate(t *testing.T) {
+	mockRepo := &mockBookRepo{
+		books: []Book{{ID: 1, WishlistID: 1, Title: "Go 101", Author: "Bob"}},
+	}
+	svc := NewBookService(mockRepo, newOwner(), nil)
+
+	book, err := svc.Get(t.Context(), 1, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Go 101", book.Title)
+
+	_, err = svc.Get(t.Context(), 1, 1, 999)
+	assert.ErrorIs(t, err, ErrBookNotFound)
+
+	// Only the author changes
+	author := " Alice "
+	book, err = svc.Update(t.Context(), 1, 1, 1, BookUpdate{Author: &author})
+	assert.NoError(t, err)
+	assert.Equal(t, "Go 101", book.Title)
+	assert.Equal(t, "Alice", book.Author)
+	assert.Equal(t, "Alice", mockRepo.books[0].Author)
+
+	// Blank titles are rejected
+	blank := ""
+	_, err = svc.Update(t.Context(), 1, 1, 1, BookUpdate{Title: &blank})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	// Other users' wishlists are off limits
+	_, err = svc.Update(t.Context(), 2, 1, 1, BookUpdate{Author: &author})
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+}
+
+// TestBookService_Metadata verifies that book metadata is normalized on add
+// and that the primary author stays in sync with the authors list.
+func TestBookService_Metadata(t *testing.T) {
+	mockRepo := &mockBookRepo{}
+	svc := NewBookService(mockRepo, newOwner(), nil)
+
+	book, _, err := svc.Add(t.Context(), 1, 1, Book{
+		ID:            42,
+		Title:         " Dune ",
+		Authors:       []string{" Frank Herbert ", ""},
+		Language:      "EN",
+		PublishedDate: "1965-08",
+		Categories:    []string{"Fiction", " "},
+	}, DuplicateReject)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), book.ID)
+	assert.Equal(t, uint(1), book.WishlistID)
+	assert.Equal(t, "Dune", book.Title)
+	assert.Equal(t, []string{"Frank Herbert"}, book.Authors)
+	assert.Equal(t, "Frank Herbert", book.Author)
+	assert.Equal(t, "en", book.Language)
+	assert.Equal(t, []string{"Fiction"}, book.Categories)
+
+	// A legacy single author becomes the authors list
+	book, _, err = svc.Add(t.Context(), 1, 1, Book{Title: "Go 101", Author: "Alice"}, DuplicateReject)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Alice"}, book.Authors)
+
+	invalid := []Book{
+		{Title: "X", PageCount: -1},
+		{Title: "X", PublishedDate: "yesterday"},
+		{Title: "X", ThumbnailURL: "cover.jpg"},
+	}
+	for _, b := range invalid {
+		_, _, err := svc.Add(t.Context(), 1, 1, b, DuplicateReject)
+		assert.ErrorIs(t, err, ErrValidation, "%+v", b)
+	}
+}
+
+// TestBookService_UpdateAuthors verifies how author changes interact with
+// the authors list during partial updates.
+func TestBookService_UpdateAuthors(t *testing.T) {
+	mockRepo := &mockBookRepo{
+		books: []Book{{ID: 1, WishlistID: 1, Title: "Go", Author: "A", Authors: []string{"A", "B"}}},
+	}
+	svc := NewBookService(mockRepo, newOwner(), nil)
+
+	// Replacing the primary author keeps the co-authors
+	author := "C"
+	book, err := svc.Update(t.Context(), 1, 1, 1, BookUpdate{Author: &author})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"C", "B"}, book.Authors)
+	assert.Equal(t, "C", book.Author)
+
+	// A full list wins over the primary author
+	authors := []string{"D"}
+	book, err = svc.Update(t.Context(), 1, 1, 1, BookUpdate{Author: &author, Authors: &authors})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"D"}, book.Authors)
+	assert.Equal(t, "D", book.Author)
+
+	// An empty list clears every author
+	authors = []string{}
+	book, err = svc.Update(t.Context(), 1, 1, 1, BookUpdate{Authors: &authors})
+	assert.NoError(t, err)
+	assert.Empty(t, book.Authors)
+	assert.Empty(t, book.Author)
+	assert.Empty(t, mockRepo.books[0].Author)
+
+	pages := 320
+	book, err = svc.Update(t.Context(), 1, 1, 1, BookUpdate{PageCount: &pages})
+	assert.NoError(t, err)
+	assert.Equal(t, 320, book.PageCount)
+	assert.Equal(t, "Go", book.Title)
+}
+
+// TestBookService_ISBN verifies that ISBNs are normalized, completed and
+// validated when books are added or updated.
+func TestBookService_ISBN(t *testing.T) {
+	mockRepo := &mockBookRepo{}
+	svc := NewBookService(mockRepo, newOwner(), nil)
+
+	book, _, err := svc.Add(t.Context(), 1, 1, Book{Title: "Go", ISBN10: "0-306-40615-2"}, DuplicateReject)
+	assert.NoError(t, err)
+	assert.Equal(t, "0306406152", book.ISBN10)
+	assert.Equal(t, "9780306406157", book.ISBN13)
+
+	book, _, err = svc.Add(t.Context(), 1, 1, Book{Title: "Go", ISBN13: "979-1-234-56789-6"}, DuplicateReject)
+	assert.NoError(t, err)
+	assert.Empty(t, book.ISBN10)
+	assert.Equal(t, "9791234567896", book.ISBN13)
+
+	invalid := []Book{
+		{Title: "X", ISBN10: "0306406153"},
+		{Title: "X", ISBN13: "0306406152"},
+		{Title: "X", ISBN10: "0306406152", ISBN13: "9780804429573"},
+	}
+	for _, b := range invalid {
+		_, _, err := svc.Add(t.Context(), 1, 1, b, DuplicateReject)
+		assert.ErrorIs(t, err, ErrValidation, "%+v", b)
+	}
+
+	// Changing one form re-derives the other
+	isbn13 := "9780804429573"
+	book, err = svc.Update(t.Context(), 1, 1, 1, BookUpdate{ISBN13: &isbn13})
+	assert.NoError(t, err)
+	assert.Equal(t, "080442957X", book.ISBN10)
+}
+
+// TestBookService_DuplicatePolicy verifies the reject, merge and allow
+// policies when the same book is added twice.
+func TestBookService_DuplicatePolicy(t *testing.T) {
+	mockRepo := &mockBookRepo{
+		books: []Book{{ID: 1, WishlistID: 1, Title: "L'Étranger", Author: "Albert Camus", Authors: []string{"Albert Camus"}}},
+	}
+	svc := NewBookService(mockRepo, newOwner(), nil)
+	dup := Book{Title: "l etranger", Author: "ALBERT CAMUS", Publisher: "Gallimard", Categories: []string{"Fiction"}}
+
+	_, _, err := svc.Add(t.Context(), 1, 1, dup, DuplicateReject)
+	assert.ErrorIs(t, err, ErrDuplicateBook)
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Contains(t, err.Error(), "book 1")
+
+	book, created, err := svc.Add(t.Context(), 1, 1, dup, DuplicateMerge)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, uint(1), book.ID)
+	assert.Equal(t, "L'Étranger", book.Title)
+	assert.Equal(t, "Gallimard", mockRepo.books[0].Publisher)
+	assert.Equal(t, []string{"Fiction"}, mockRepo.books[0].Categories)
+	assert.Len(t, mockRepo.books, 1)
+
+	book, created, err = svc.Add(t.Context(), 1, 1, dup, DuplicateAllow)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, uint(2), book.ID)
+	assert.Len(t, mockRepo.books, 2)
+}
+
+// TestBookService_Duplicates verifies grouping of likely duplicates by ISBN
+// and by folded title and author.
+func TestBookService_Duplicates(t *testing.T) {
+	mockRepo := &mockBookRepo{books: []Book{
+		{ID: 1, WishlistID: 1, Title: "Go", Author: "Alan", ISBN13: "9780306406157"},
+		{ID: 2, WishlistID: 1, Title: "Go (2nd ed.)", Author: "Alan", ISBN10: "0306406152"},
+		{ID: 3, WishlistID: 1, Title: "Cien años de soledad", Author: "García Márquez"},
+		{ID: 4, WishlistID: 1, Title: "Cien Anos de Soledad", Author: "Garcia Marquez"},
+		{ID: 5, WishlistID: 1, Title: "Go", Author: "Alan", ISBN13: "9780804429573"}, // other edition
+		{ID: 6, WishlistID: 1, Title: "Unique", Author: "Nobody"},
+	}}
+	svc := NewBookService(mockRepo, newOwner(), nil)
+
+	groups, err := svc.Duplicates(t.Context(), 1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, []string{MatchISBN}, groups[0].MatchedBy)
+	assert.Equal(t, uint(1), groups[0].Books[0].ID)
+	assert.Equal(t, uint(2), groups[0].Books[1].ID)
+	assert.Len(t, groups[0].Books, 2)
+	assert.Equal(t, []string{MatchTitleAuthor}, groups[1].MatchedBy)
+	assert.Len(t, groups[1].Books, 2)
+
+	_, err = svc.Duplicates(t.Context(), 1, 2)
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+}
+
+// mockVolumes is a GoogleBooksUsecase that knows a single volume.
+type mockVolumes struct{ calls int }
+
+func (m *mockVolumes) Search(context.Context, SearchParams) (*SearchResult, error) {
+	return &SearchResult{}, nil
+}
+func (m *mockVolumes) GetVolume(ctx context.Context, id string) (*GoogleBook, error) {
+	m.calls++
+	if id != "vol1" {
+		return nil, ErrVolumeNotFound
+	}
+	return &GoogleBook{
+		ID:                  "vol1",
+		Title:               "The Go Programming Language",
+		Authors:             []string{"Alan Donovan", "Brian Kernighan"},
+		PublishedDate:       "2015-11-16T00:00:00Z",
+		PageCount:           380,
+		Categories:          []string{"Computers"},
+		IndustryIdentifiers: []IndustryIdentifier{{Type: "ISBN_10", Identifier: "0134190440"}, {Type: "OTHER", Identifier: "UOM:39015"}},
+		ThumbnailURL:        "https://books.google.com/cover.jpg",
+	}, nil
+}
+
+// TestBookService_AddFromGoogle verifies that a volume is imported with its
+// metadata and external ID, that unknown volumes are rejected and that
+// re-importing the same volume is detected as a duplicate.
+func TestBookService_AddFromGoogle(t *testing.T) {
+	mockRepo := &mockBookRepo{}
+	google := &mockVolumes{}
+	svc := NewBookService(mockRepo, newOwner(), google)
+
+	book, created, err := svc.AddFromGoogle(t.Context(), 1, 1, "vol1", DuplicateReject)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "The Go Programming Language", book.Title)
+	assert.Equal(t, "Alan Donovan", book.Author)
+	assert.Equal(t, "2015-11-16", book.PublishedDate)
+	assert.Equal(t, "0134190440", book.ISBN10)
+	assert.Equal(t, "9780134190440", book.ISBN13)
+	assert.Equal(t, SourceGoogleBooks, book.ExternalSource)
+	assert.Equal(t, "vol1", book.ExternalID)
+
+	_, _, err = svc.AddFromGoogle(t.Context(), 1, 1, "vol1", DuplicateReject)
+	assert.ErrorIs(t, err, ErrDuplicateBook)
+
+	_, _, err = svc.AddFromGoogle(t.Context(), 1, 1, "nope", DuplicateReject)
+	assert.ErrorIs(t, err, ErrVolumeNotFound)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Ownership is checked before calling Google
+	calls := google.calls
+	_, _, err = svc.AddFromGoogle(t.Context(), 1, 2, "vol1", DuplicateReject)
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+	assert.Equal(t, calls, google.calls)
+}
+
+// TestGoogleBook_ToBook verifies the mapping of inconsistent upstream data.
+func TestGoogleBook_ToBook(t *testing.T) {
+	b := GoogleBook{
+		ID:            "x",
+		Title:         "T",
+		PublishedDate: "circa 1900",
+		IndustryIdentifiers: []IndustryIdentifier{
+			{Type: "ISBN_10", Identifier: "0306406152"},
+			{Type: "ISBN_13", Identifier: "9780804429573"},
+		},
+	}.ToBook()
+	assert.Empty(t, b.PublishedDate)
+	assert.Empty(t, b.ISBN10)
+	assert.Equal(t, "9780804429573", b.ISBN13)
+	assert.Equal(t, "1965", coercePublishedDate("1965"))
+	assert.Equal(t, "1965-08", coercePublishedDate("1965-08"))
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+//
+// ─────────────────────────── CATALOG SERVICE ───────────────────────────
+//
+
+// ProviderAggregate is the provider name that searches every configured
+// provider concurrently and merges their results.
+const ProviderAggregate = "aggregate"
+
+// CatalogProvider is a named catalogue provider.
+type CatalogProvider struct {
+	Name   string       // Name used in the provider query parameter, e.g. SourceGoogleBooks
+	Client BookSearcher // Provider client
+}
+
+// catalogService implements CatalogUsecase over an ordered list of providers.
+type catalogService struct {
+	providers []CatalogProvider
+	def       string
+}
+
+// NewCatalogService creates a catalogue over providers, listed in order of
+// precedence for aggregate searches. defaultProvider is used when a search
+// names no provider; when empty, the first provider is the default.
+func NewCatalogService(defaultProvider string, providers ...CatalogProvider) CatalogUsecase {
+	if defaultProvider == "" && len(providers) > 0 {
+		defaultProvider = providers[0].Name
+	}
+	return &catalogService{providers: providers, def: defaultProvider}
+}
+
+// Providers lists the configured provider names in order of precedence.
+func (c *catalogService) Providers() []string {
+	names := make([]string, len(c.providers))
+	for i, p := range c.providers {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Search runs params against the named provider and tags every item with it.
+// ProviderAggregate delegates to aggregate.
+func (c *catalogService) Search(ctx context.Context, provider string, params SearchParams) (*SearchResult, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == "" {
+		provider = c.def
+	}
+	if provider == ProviderAggregate {
+		return c.aggregate(ctx, params)
+	}
+	for _, p := range c.providers {
+		if p.Name != provider {
+			continue
+		}
+		res, err := p.Client.Search(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		res.Provider = p.Name
+		for i := range res.Items {
+			res.Items[i].Provider = p.Name
+		}
+		return res, nil
+	}
+	return nil, validationErrorf("unknown provider %q (use %s or %s)", provider, strings.Join(c.Providers(), ", "), ProviderAggregate)
+}
+
+// aggregate queries every provider concurrently and merges the volumes that
+// share an ISBN. Each field is taken from the first provider, in order of
+// precedence, that has it, and FieldSources records which one that was.
+//
+// Providers that fail are listed in ProviderErrors; the search only fails
+// when all of them do. TotalItems is the largest provider estimate, and a
+// page may hold up to MaxResults items per provider.
+func (c *catalogService) aggregate(ctx context.Context, params SearchParams) (*SearchResult, error) {
+	if err := params.normalize(); err != nil {
+		return nil, err
+	}
+
+	results := make([]*SearchResult, len(c.providers))
+	errs := make([]error, len(c.providers))
+	var wg sync.WaitGroup
+	for i, p := range c.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = p.Client.Search(ctx, params)
+		}()
+	}
+	wg.Wait()
+
+	out := &SearchResult{
+		StartIndex: params.StartIndex,
+		MaxResults: params.MaxResults,
+		Items:      []GoogleBook{},
+		Provider:   ProviderAggregate,
+	}
+	for i, p := range c.providers {
+		if errs[i] != nil {
+			if out.ProviderErrors == nil {
+				out.ProviderErrors = map[string]string{}
+			}
+			out.ProviderErrors[p.Name] = errs[i].Error()
+			continue
+		}
+		res := results[i]
+		out.TotalItems = max(out.TotalItems, res.TotalItems)
+		if res.HasMore {
+			out.HasMore = true
+			out.NextStartIndex = max(out.NextStartIndex, res.NextStartIndex)
+		}
+		for j := range res.Items {
+			out.Items = mergeVolumes(out.Items, &res.Items[j], p.Name)
+		}
+	}
+	if len(c.providers) > 0 && len(out.ProviderErrors) == len(c.providers) {
+		return nil, errs[0]
+	}
+	return out, nil
+}
+
+// mergeVolumes merges v into the item of items that shares one of its ISBNs,
+// or appends it as a new item. Volumes without ISBNs are never merged.
+func mergeVolumes(items []GoogleBook, v *GoogleBook, provider string) []GoogleBook {
+	isbns := volumeISBNs(v)
+	for i := range items {
+		if sharesISBN(volumeISBNs(&items[i]), isbns) {
+			fillVolume(&items[i], v, provider)
+			items[i].ISBNs = append(items[i].ISBNs, isbns...)
+			return items
+		}
+	}
+	item := GoogleBook{
+		ID:                  v.ID,
+		Provider:            provider,
+		Authors:             []string{},
+		IndustryIdentifiers: []IndustryIdentifier{},
+		FieldSources:        map[string]string{},
+		ISBNs:               isbns,
+	}
+	fillVolume(&item, v, provider)
+	return append(items, item)
+}
+
+// fillVolume copies the fields of src into the empty fields of dst and
+// records provider as their source.
+func fillVolume(dst, src *GoogleBook, provider string) {
+	take := func(field string, missing, present bool, set func()) {
+		if missing && present {
+			set()
+			dst.FieldSources[field] = provider
+		}
+	}
+	take("title", dst.Title == "", src.Title != "", func() { dst.Title = src.Title })
+	take("authors", len(dst.Authors) == 0, len(src.Authors) > 0, func() { dst.Authors, dst.Author = src.Authors, src.Author })
+	take("publisher", dst.Publisher == "", src.Publisher != "", func() { dst.Publisher = src.Publisher })
+	take("published_date", dst.PublishedDate == "", src.PublishedDate != "", func() { dst.PublishedDate = src.PublishedDate })
+	take("description", dst.Description == "", src.Description != "", func() { dst.Description = src.Description })
+	take("page_count", dst.PageCount == 0, src.PageCount > 0, func() { dst.PageCount = src.PageCount })
+	take("language", dst.Language == "", src.Language != "", func() { dst.Language = src.Language })
+	take("categories", len(dst.Categories) == 0, len(src.Categories) > 0, func() { dst.Categories = src.Categories })
+	take("industry_identifiers", len(dst.IndustryIdentifiers) == 0, len(src.IndustryIdentifiers) > 0, func() {
+		dst.IndustryIdentifiers = src.IndustryIdentifiers
+	})
+	take("thumbnail_url", dst.ThumbnailURL == "", src.ThumbnailURL != "", func() { dst.ThumbnailURL = src.ThumbnailURL })
+}
+
+// volumeISBNs returns the ISBN-13s known for v, from its identifiers and,
+// for providers that list several editions, the extra ISBNs they reported.
+func volumeISBNs(v *GoogleBook) []string {
+	isbns := append([]string(nil), v.ISBNs...)
+	for _, id := range v.IndustryIdentifiers {
+		if c := CheckISBN(id.Identifier); c.Valid {
+			isbns = append(isbns, c.ISBN13)
+		}
+	}
+	return isbns
+}
+
+// sharesISBN reports whether a and b have an ISBN in common.
+func sharesISBN(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSearcher returns a fixed result or error, optionally after a delay.
+type stubSearcher struct {
+	res   *service.SearchResult
+	err   error
+	delay time.Duration
+	calls int
+}
+
+func (s *stubSearcher) Search(ctx context.Context, p service.SearchParams) (*service.SearchResult, error) {
+	s.calls++
+	time.Sleep(s.delay)
+	if s.err != nil {
+		return nil, s.err
+	}
+	res := *s.res
+	res.Items = append([]service.GoogleBook(nil), s.res.Items...)
+	return &res, nil
+}
+
+// TestCatalog_Search verifies provider selection, the default provider and
+// the error for unknown provider names.
+func TestCatalog_Search(t *testing.T) {
+	google := &stubSearcher{res: &service.SearchResult{Items: []service.GoogleBook{{ID: "g1"}}}}
+	library := &stubSearcher{res: &service.SearchResult{Items: []service.GoogleBook{{ID: "OL1W"}}}}
+	catalog := service.NewCatalogService("",
+		service.CatalogProvider{Name: service.SourceGoogleBooks, Client: google},
+		service.CatalogProvider{Name: service.SourceOpenLibrary, Client: library},
+	)
+	assert.Equal(t, []string{"google_books", "open_library"}, catalog.Providers())
+
+	res, err := catalog.Search(t.Context(), "", service.SearchParams{Query: "go"})
+	assert.NoError(t, err)
+	assert.Equal(t, "google_books", res.Provider)
+	assert.Equal(t, "google_books", res.Items[0].Provider)
+
+	res, err = catalog.Search(t.Context(), " Open_Library ", service.SearchParams{Query: "go"})
+	assert.NoError(t, err)
+	assert.Equal(t, "open_library", res.Provider)
+	assert.Equal(t, "OL1W", res.Items[0].ID)
+
+	_, err = catalog.Search(t.Context(), "amazon", service.SearchParams{Query: "go"})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.Contains(t, err.Error(), "google_books, open_library or aggregate")
+
+	catalog = service.NewCatalogService(service.SourceOpenLibrary,
+		service.CatalogProvider{Name: service.SourceGoogleBooks, Client: google},
+		service.CatalogProvider{Name: service.SourceOpenLibrary, Client: library},
+	)
+	res, err = catalog.Search(t.Context(), "", service.SearchParams{Query: "go"})
+	assert.NoError(t, err)
+	assert.Equal(t, "open_library", res.Provider)
+}
+
+// TestCatalog_Aggregate verifies that providers are queried concurrently,
+// that volumes sharing an ISBN are merged field by field with their sources
+// recorded, and that unmatched volumes are kept.
+func TestCatalog_Aggregate(t *testing.T) {
+	google := &stubSearcher{delay: 50 * time.Millisecond, res: &service.SearchResult{
+		TotalItems: 100, HasMore: true, NextStartIndex: 2,
+		Items: []service.GoogleBook{
+			{
+				ID: "zyTCAlFPjgYC", Title: "The Go Programming Language", Authors: []string{"Alan A. A. Donovan"}, Author: "Alan A. A. Donovan",
+				Description:         "The authoritative resource.",
+				IndustryIdentifiers: []service.IndustryIdentifier{{Type: "ISBN_10", Identifier: "0134190440"}},
+			},
+			{ID: "noisbn", Title: "Go in Action"},
+		},
+	}}
+
+	// Open Library lists the ISBNs of every edition; the work matches Google's
+	// volume through a secondary ISBN.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"numFound": 3, "docs": [
+			{"key": "/works/OL17930368W", "title": "Go Programming Language", "number_of_pages_median": 380,
+			 "cover_i": 42, "isbn": ["9780134190563", "9780134190440"]},
+			{"key": "/works/OL2W", "title": "Learning Go", "isbn": ["9781492077213"]}
+		]}`))
+	}))
+	defer srv.Close()
+	library := service.NewOpenLibraryService(service.OpenLibraryOptions{BaseURL: srv.URL, CoversURL: "https://covers.example.com"})
+
+	catalog := service.NewCatalogService("",
+		service.CatalogProvider{Name: service.SourceGoogleBooks, Client: google},
+		service.CatalogProvider{Name: service.SourceOpenLibrary, Client: library},
+	)
+	start := time.Now()
+	res, err := catalog.Search(t.Context(), service.ProviderAggregate, service.SearchParams{Query: "go", MaxResults: 2})
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 95*time.Millisecond, "providers must be queried concurrently")
+
+	assert.Equal(t, "aggregate", res.Provider)
+	assert.Equal(t, 100, res.TotalItems)
+	assert.True(t, res.HasMore)
+	assert.Equal(t, 2, res.NextStartIndex)
+	assert.Empty(t, res.ProviderErrors)
+	if !assert.Len(t, res.Items, 3) {
+		return
+	}
+
+	merged := res.Items[0]
+	assert.Equal(t, "zyTCAlFPjgYC", merged.ID)
+	assert.Equal(t, "google_books", merged.Provider)
+	assert.Equal(t, "The Go Programming Language", merged.Title)
+	assert.Equal(t, 380, merged.PageCount)
+	assert.Equal(t, "https://covers.example.com/b/id/42-M.jpg", merged.ThumbnailURL)
+	assert.Equal(t, map[string]string{
+		"title":                "google_books",
+		"authors":              "google_books",
+		"description":          "google_books",
+		"industry_identifiers": "google_books",
+		"page_count":           "open_library",
+		"thumbnail_url":        "open_library",
+	}, merged.FieldSources)
+
+	assert.Equal(t, "noisbn", res.Items[1].ID)
+	assert.Equal(t, "OL2W", res.Items[2].ID)
+	assert.Equal(t, "open_library", res.Items[2].Provider)
+	assert.Equal(t, "open_library", res.Items[2].FieldSources["title"])
+}
+
+// TestCatalog_AggregateErrors verifies partial failures are reported per
+// provider and that the search fails only when every provider fails.
+func TestCatalog_AggregateErrors(t *testing.T) {
+	down := service.NewError(service.ErrUpstreamUnavailable, "open library is unavailable")
+	google := &stubSearcher{res: &service.SearchResult{Items: []service.GoogleBook{{ID: "g1", Title: "Go"}}}}
+	library := &stubSearcher{err: down}
+	catalog := service.NewCatalogService("",
+		service.CatalogProvider{Name: service.SourceGoogleBooks, Client: google},
+		service.CatalogProvider{Name: service.SourceOpenLibrary, Client: library},
+	)
+
+	res, err := catalog.Search(t.Context(), service.ProviderAggregate, service.SearchParams{Query: "go"})
+	assert.NoError(t, err)
+	assert.Len(t, res.Items, 1)
+	assert.Equal(t, map[string]string{"open_library": "open library is unavailable"}, res.ProviderErrors)
+
+	google.err = service.NewError(service.ErrUpstreamQuota, "google books quota exceeded, try again later")
+	_, err = catalog.Search(t.Context(), service.ProviderAggregate, service.SearchParams{Query: "go"})
+	assert.ErrorIs(t, err, service.ErrUpstreamQuota)
+
+	// Invalid parameters fail before any provider is called
+	google.calls, library.calls = 0, 0
+	_, err = catalog.Search(t.Context(), service.ProviderAggregate, service.SearchParams{})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.Zero(t, google.calls+library.calls)
+}
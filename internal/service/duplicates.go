@@ -0,0 +1,232 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+//
+// ─────────────────────────── DUPLICATE POLICY ───────────────────────────
+//
+
+// DuplicatePolicy decides what Add does when the wishlist already holds the book.
+type DuplicatePolicy string
+
+const (
+	// DuplicateReject refuses the new book with an ErrDuplicateBook conflict.
+	DuplicateReject DuplicatePolicy = "reject"
+
+	// DuplicateMerge fills empty fields of the stored book from the new one
+	// and returns the stored book instead of inserting a copy.
+	DuplicateMerge DuplicatePolicy = "merge"
+
+	// DuplicateAllow inserts the book even if it is a duplicate.
+	DuplicateAllow DuplicatePolicy = "allow"
+)
+
+// ParseDuplicatePolicy converts a client-supplied policy name.
+// An empty string selects DuplicateReject.
+func ParseDuplicatePolicy(s string) (DuplicatePolicy, error) {
+	switch p := DuplicatePolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return DuplicateReject, nil
+	case DuplicateReject, DuplicateMerge, DuplicateAllow:
+		return p, nil
+	default:
+		return "", validationErrorf("unknown duplicate policy %q (use reject, merge or allow)", s)
+	}
+}
+
+//
+// ─────────────────────────── MATCHING ───────────────────────────
+//
+
+// Reasons reported for a duplicate match.
+const (
+	MatchExternalID  = "external_id"  // Imported from the same catalogue entry
+	MatchISBN        = "isbn"         // Same ISBN-13 (after normalization)
+	MatchTitleAuthor = "title_author" // Same title and primary author, ignoring case and accents
+)
+
+// DuplicateGroup is a set of books in one wishlist that look like the same book.
+type DuplicateGroup struct {
+	MatchedBy []string // Reasons that linked the books (MatchExternalID, MatchISBN, MatchTitleAuthor)
+	Books     []Book   // Books in the group, ordered by ID
+}
+
+// matchBooks reports whether a and b look like the same book and why.
+// Books imported from the same catalogue entry always match. Books that both
+// carry an ISBN are compared by ISBN only, so different editions of the same
+// title are not flagged; otherwise the folded title and primary author must match.
+func matchBooks(a, b *Book) (string, bool) {
+	if a.ExternalID != "" && a.ExternalSource == b.ExternalSource && a.ExternalID == b.ExternalID {
+		return MatchExternalID, true
+	}
+	isbnA, isbnB := canonicalISBN(a), canonicalISBN(b)
+	if isbnA != "" && isbnB != "" {
+		return MatchISBN, isbnA == isbnB
+	}
+	if foldText(a.Title) == foldText(b.Title) && foldText(a.Author) == foldText(b.Author) {
+		return MatchTitleAuthor, true
+	}
+	return "", false
+}
+
+// findDuplicate returns the first book in books that matches b.
+func findDuplicate(books []Book, b *Book) (*Book, string) {
+	for i := range books {
+		if reason, ok := matchBooks(&books[i], b); ok {
+			return &books[i], reason
+		}
+	}
+	return nil, ""
+}
+
+// groupDuplicates links every pair of matching books and returns the groups
+// with more than one member, ordered by their lowest book ID.
+func groupDuplicates(books []Book) []DuplicateGroup {
+	parent := make([]int, len(books))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	reasons := map[int]map[string]bool{}
+	for i := range books {
+		for j := i + 1; j < len(books); j++ {
+			reason, ok := matchBooks(&books[i], &books[j])
+			if !ok {
+				continue
+			}
+			ri, rj := find(i), find(j)
+			if ri != rj {
+				if rj < ri {
+					ri, rj = rj, ri
+				}
+				parent[rj] = ri
+				for r := range reasons[rj] {
+					addReason(reasons, ri, r)
+				}
+				delete(reasons, rj)
+			}
+			addReason(reasons, ri, reason)
+		}
+	}
+
+	members := map[int][]Book{}
+	var roots []int
+	for i := range books {
+		r := find(i)
+		if _, seen := members[r]; !seen {
+			roots = append(roots, r)
+		}
+		members[r] = append(members[r], books[i])
+	}
+
+	var groups []DuplicateGroup
+	for _, r := range roots {
+		if len(members[r]) < 2 {
+			continue
+		}
+		g := DuplicateGroup{Books: members[r]}
+		for _, reason := range []string{MatchExternalID, MatchISBN, MatchTitleAuthor} {
+			if reasons[r][reason] {
+				g.MatchedBy = append(g.MatchedBy, reason)
+			}
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// addReason records that the group rooted at root was linked by reason.
+func addReason(reasons map[int]map[string]bool, root int, reason string) {
+	if reasons[root] == nil {
+		reasons[root] = map[string]bool{}
+	}
+	reasons[root][reason] = true
+}
+
+// canonicalISBN returns the ISBN-13 of b, deriving it from the ISBN-10 when
+// only that is stored. Returns "" if the book has no valid ISBN.
+func canonicalISBN(b *Book) string {
+	if isbn13 := NormalizeISBN(b.ISBN13); ValidISBN13(isbn13) {
+		return isbn13
+	}
+	if isbn10 := NormalizeISBN(b.ISBN10); ValidISBN10(isbn10) {
+		return ISBN10To13(isbn10)
+	}
+	return ""
+}
+
+// foldText lower-cases s, strips diacritics and collapses punctuation and
+// whitespace into single spaces, so "L'Étranger" and "l etranger" compare equal.
+func foldText(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+	return strings.Join(strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+//
+// ─────────────────────────── MERGING ───────────────────────────
+//
+
+// mergeBook fills the empty fields of dst with the values from src.
+// Categories are combined; populated fields of dst are never overwritten.
+func mergeBook(dst *Book, src Book) {
+	if len(dst.Authors) == 0 {
+		dst.Authors, dst.Author = src.Authors, src.Author
+	}
+	if dst.ISBN10 == "" && dst.ISBN13 == "" {
+		dst.ISBN10, dst.ISBN13 = src.ISBN10, src.ISBN13
+	}
+	if dst.ExternalID == "" {
+		dst.ExternalSource, dst.ExternalID = src.ExternalSource, src.ExternalID
+	}
+	fillEmpty(&dst.Publisher, src.Publisher)
+	fillEmpty(&dst.PublishedDate, src.PublishedDate)
+	fillEmpty(&dst.Language, src.Language)
+	fillEmpty(&dst.Description, src.Description)
+	fillEmpty(&dst.ThumbnailURL, src.ThumbnailURL)
+	fillEmpty(&dst.Notes, src.Notes)
+	if dst.PageCount == 0 {
+		dst.PageCount = src.PageCount
+	}
+	for _, c := range src.Categories {
+		if !containsFold(dst.Categories, c) {
+			dst.Categories = append(dst.Categories, c)
+		}
+	}
+}
+
+// fillEmpty assigns src to *dst when *dst is empty.
+func fillEmpty(dst *string, src string) {
+	if *dst == "" {
+		*dst = src
+	}
+}
+
+// containsFold reports whether list contains s, ignoring case and accents.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if foldText(v) == foldText(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,506 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+//
+// ─────────────────────────── METADATA ENRICHMENT ───────────────────────────
+//
+
+// Default values applied by NewEnricher when the options leave them empty.
+const (
+	DefaultEnrichInterval       = 10 * time.Minute
+	DefaultEnrichBatchSize      = 50
+	DefaultEnrichLookupInterval = time.Second
+	DefaultEnrichMinConfidence  = 0.8
+	DefaultEnrichJobRetention   = time.Hour
+)
+
+// EnrichmentOptions configures the metadata enricher.
+type EnrichmentOptions struct {
+	Interval       time.Duration    // How often Run looks for books lacking metadata (default: DefaultEnrichInterval)
+	BatchSize      int              // Books handled per Run iteration (default: DefaultEnrichBatchSize)
+	LookupInterval time.Duration    // Minimum time between two Google Books lookups (default: DefaultEnrichLookupInterval)
+	MinConfidence  float64          // Lowest match confidence that fills in fields (default: DefaultEnrichMinConfidence)
+	JobRetention   time.Duration    // How long finished jobs can still be polled (default: DefaultEnrichJobRetention)
+	Now            func() time.Time // Clock (default: time.Now)
+}
+
+// Enrichment job states.
+const (
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// EnrichStats counts the outcome of an enrichment run.
+type EnrichStats struct {
+	Processed int // Books looked up
+	Enriched  int // Books that gained at least one field
+	Skipped   int // Books without a confident match or with nothing to fill in
+	Failed    int // Books that could not be saved
+}
+
+// EnrichJob is an on-demand enrichment of one wishlist.
+type EnrichJob struct {
+	ID         string
+	UserID     uint
+	WishlistID uint
+	Status     string // JobRunning, JobDone or JobFailed
+	Total      int    // Books lacking metadata when the job started
+	EnrichStats
+	Error      string     // Why the job failed (JobFailed only)
+	StartedAt  time.Time  // When the job was created
+	FinishedAt *time.Time // When the job ended (nil while running)
+}
+
+// Enricher fills in missing book metadata from Google Books, both in the
+// background (Run) and on demand per wishlist (Enrich).
+//
+// Each book is looked up by ISBN when it has one, otherwise by title and
+// author. The best result is scored against the book; when the confidence
+// reaches MinConfidence, empty fields are filled in and recorded in
+// Book.AutoFilled. Populated fields are never overwritten.
+type Enricher struct {
+	books     BookRepository
+	wishlists WishlistRepository
+	google    GoogleBooksUsecase
+
+	interval      time.Duration
+	batchSize     int
+	minConfidence float64
+	retention     time.Duration
+	now           func() time.Time
+	limiter       *rateLimiter
+
+	mu      sync.Mutex
+	jobs    map[string]*EnrichJob
+	running map[uint]string // Wishlist ID → ID of its running job
+}
+
+// NewEnricher creates an enricher from opts, applying the defaults above to empty fields.
+func NewEnricher(books BookRepository, wishlists WishlistRepository, google GoogleBooksUsecase, opts EnrichmentOptions) *Enricher {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultEnrichInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultEnrichBatchSize
+	}
+	if opts.LookupInterval <= 0 {
+		opts.LookupInterval = DefaultEnrichLookupInterval
+	}
+	if opts.MinConfidence <= 0 {
+		opts.MinConfidence = DefaultEnrichMinConfidence
+	}
+	if opts.JobRetention <= 0 {
+		opts.JobRetention = DefaultEnrichJobRetention
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Enricher{
+		books:         books,
+		wishlists:     wishlists,
+		google:        google,
+		interval:      opts.Interval,
+		batchSize:     opts.BatchSize,
+		minConfidence: opts.MinConfidence,
+		retention:     opts.JobRetention,
+		now:           opts.Now,
+		limiter:       &rateLimiter{interval: opts.LookupInterval},
+		jobs:          map[string]*EnrichJob{},
+		running:       map[uint]string{},
+	}
+}
+
+// Run enriches books lacking metadata every Interval until ctx is cancelled,
+// starting immediately. report, if not nil, receives the outcome of each batch.
+func (e *Enricher) Run(ctx context.Context, report func(EnrichStats, error)) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		stats, err := e.EnrichPending(ctx)
+		if report != nil && ctx.Err() == nil {
+			report(stats, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// EnrichPending enriches one batch of books, across all wishlists, that lack
+// metadata and were never enriched. It stops early on Google Books errors,
+// leaving the remaining books for the next batch.
+func (e *Enricher) EnrichPending(ctx context.Context) (EnrichStats, error) {
+	books, err := e.books.ListMissingMetadata(ctx, e.batchSize)
+	if err != nil {
+		return EnrichStats{}, err
+	}
+	var stats EnrichStats
+	for i := range books {
+		if err := e.enrichBook(ctx, &books[i], &stats); err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// Enrich starts an enrichment job for the books of a wishlist owned by the
+// user that lack an ISBN or a cover, including books enriched before.
+// The job outlives the request: it keeps the values of ctx but not its
+// cancellation or deadline.
+func (e *Enricher) Enrich(ctx context.Context, userID, wishlistID uint) (*EnrichJob, error) {
+	if _, err := getOwnedWishlist(ctx, e.wishlists, userID, wishlistID); err != nil {
+		return nil, err
+	}
+
+	books, err := e.books.List(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Book
+	for _, b := range books {
+		if needsMetadata(&b) {
+			pending = append(pending, b)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if id, ok := e.running[wishlistID]; ok {
+		job := *e.jobs[id]
+		return &job, nil
+	}
+	e.pruneJobs()
+
+	job := &EnrichJob{
+		ID:         newJobID(),
+		UserID:     userID,
+		WishlistID: wishlistID,
+		Status:     JobRunning,
+		Total:      len(pending),
+		StartedAt:  e.now(),
+	}
+	e.jobs[job.ID] = job
+	e.running[wishlistID] = job.ID
+	go e.runJob(context.WithoutCancel(ctx), job.ID, pending)
+
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// Job returns a snapshot of a job started by the user.
+// Returns ErrEnrichJobNotFound for unknown, expired or foreign jobs.
+func (e *Enricher) Job(ctx context.Context, userID uint, jobID string) (*EnrichJob, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	job, ok := e.jobs[jobID]
+	if !ok || job.UserID != userID {
+		return nil, ErrEnrichJobNotFound
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// runJob enriches books on behalf of a job and publishes its progress.
+func (e *Enricher) runJob(ctx context.Context, id string, books []Book) {
+	var jobErr error
+	for i := range books {
+		var stats EnrichStats
+		jobErr = e.enrichBook(ctx, &books[i], &stats)
+		e.mu.Lock()
+		job := e.jobs[id]
+		job.Processed += stats.Processed
+		job.Enriched += stats.Enriched
+		job.Skipped += stats.Skipped
+		job.Failed += stats.Failed
+		e.mu.Unlock()
+		if jobErr != nil {
+			break
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	job := e.jobs[id]
+	finished := e.now()
+	job.FinishedAt = &finished
+	job.Status = JobDone
+	if jobErr != nil {
+		job.Status, job.Error = JobFailed, jobErr.Error()
+	}
+	delete(e.running, job.WishlistID)
+}
+
+// pruneJobs forgets jobs that finished more than the retention period ago.
+// The caller must hold e.mu.
+func (e *Enricher) pruneJobs() {
+	cutoff := e.now().Add(-e.retention)
+	for id, job := range e.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+			delete(e.jobs, id)
+		}
+	}
+}
+
+// enrichBook looks b up, fills in its missing fields when the best match is
+// confident enough and saves the attempt. Google Books quota and availability
+// errors and the cancellation of ctx are returned so that callers can stop;
+// other failures are counted in stats.
+//
+// The user may edit or delete the book during the lookup, so the match is
+// applied to the book as it is afterwards, and only the fields enrichment
+// sets are saved.
+func (e *Enricher) enrichBook(ctx context.Context, b *Book, stats *EnrichStats) error {
+	if err := e.limiter.wait(ctx); err != nil {
+		return err
+	}
+	match, err := e.lookup(ctx, b)
+	if err != nil {
+		if isUpstreamError(err) || ctx.Err() != nil {
+			return err
+		}
+		match = nil // e.g. a title Google refuses to search for
+	}
+	stats.Processed++
+
+	cur, err := e.books.Get(ctx, b.WishlistID, b.ID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			stats.Skipped++ // Deleted during the lookup
+		} else {
+			stats.Failed++
+		}
+		return nil
+	}
+	*b = *cur
+
+	now := e.now()
+	b.EnrichedAt = &now
+	b.EnrichmentConfidence = 0
+	if match != nil {
+		b.EnrichmentConfidence = matchConfidence(b, match)
+	}
+	fields := []string{"EnrichedAt", "EnrichmentConfidence", "AutoFilled"}
+	var filled []string
+	if match != nil && b.EnrichmentConfidence >= e.minConfidence {
+		filled = fillMissing(b, match.ToBook())
+		for _, f := range filled {
+			if !containsFold(b.AutoFilled, f) {
+				b.AutoFilled = append(b.AutoFilled, f)
+			}
+			fields = append(fields, autoFillFields[f]...)
+		}
+	}
+
+	err = normalizeBook(b)
+	if err == nil {
+		err = e.books.UpdateFields(ctx, b, fields...)
+	}
+	switch {
+	case err != nil:
+		stats.Failed++
+	case len(filled) == 0:
+		stats.Skipped++
+	default:
+		stats.Enriched++
+	}
+	return nil
+}
+
+// lookup searches Google Books for b and returns the best scoring result,
+// or nil if no result matches at all.
+// Books with an ISBN are searched by ISBN, others by title and author.
+func (e *Enricher) lookup(ctx context.Context, b *Book) (*GoogleBook, error) {
+	params := SearchParams{Title: b.Title, Author: b.Author, MaxResults: 5, PrintType: "books"}
+	if isbn := canonicalISBN(b); isbn != "" {
+		params = SearchParams{ISBN: isbn, MaxResults: 5}
+	}
+	res, err := e.google.Search(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	var best *GoogleBook
+	bestScore := 0.0
+	for i := range res.Items {
+		if score := matchConfidence(b, &res.Items[i]); score > bestScore {
+			best, bestScore = &res.Items[i], score
+		}
+	}
+	return best, nil
+}
+
+// isUpstreamError reports whether err means Google Books could not serve the
+// request, as opposed to a problem with this particular book. A rejected
+// request (ErrUpstreamBadRequest) is about the book's search terms, so it is
+// treated as a lookup without a match rather than stopping the worker.
+func isUpstreamError(err error) bool {
+	return errors.Is(err, ErrUpstreamQuota) || errors.Is(err, ErrUpstreamUnavailable)
+}
+
+// needsMetadata reports whether b lacks an ISBN or a cover image.
+func needsMetadata(b *Book) bool {
+	return (b.ISBN10 == "" && b.ISBN13 == "") || b.ThumbnailURL == ""
+}
+
+//
+// ─────────────────────────── MATCH SCORING ───────────────────────────
+//
+
+// matchConfidence scores how likely v describes book b, from 0 to 1.
+// A shared ISBN is a certain match; otherwise the title weighs 60% and
+// the primary author 40%.
+func matchConfidence(b *Book, v *GoogleBook) float64 {
+	if isbn := canonicalISBN(b); isbn != "" {
+		if sharesISBN(volumeISBNs(v), []string{isbn}) {
+			return 1
+		}
+		return 0
+	}
+	score := 0.6*titleSimilarity(b.Title, v.Title) + 0.4*authorSimilarity(b.Author, v.Authors)
+	return math.Round(score*100) / 100
+}
+
+// titleSimilarity compares two titles ignoring case, accents and punctuation.
+// A candidate that only adds a subtitle scores 0.9; other titles score the
+// share of words they have in common.
+func titleSimilarity(title, candidate string) float64 {
+	a, b := foldText(title), foldText(candidate)
+	switch {
+	case a == "" || b == "":
+		return 0
+	case a == b:
+		return 1
+	case strings.HasPrefix(b, a+" "):
+		return 0.9
+	}
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	common := 0
+	for _, w := range wa {
+		for _, x := range wb {
+			if w == x {
+				common++
+				break
+			}
+		}
+	}
+	return float64(common) / float64(len(wa)+len(wb)-common)
+}
+
+// authorSimilarity compares the book's author with the candidate's authors:
+// 1 for the same name, 0.8 for the same surname and 0.5 when the book has no
+// author to compare.
+func authorSimilarity(author string, candidates []string) float64 {
+	a := foldText(author)
+	if a == "" {
+		return 0.5
+	}
+	best := 0.0
+	for _, c := range candidates {
+		fc := foldText(c)
+		if fc == a {
+			return 1
+		}
+		if surname(fc) != "" && surname(fc) == surname(a) {
+			best = 0.8
+		}
+	}
+	return best
+}
+
+// surname returns the last word of a folded name.
+func surname(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ""
+	}
+	return words[len(words)-1]
+}
+
+// autoFillFields maps the JSON names returned by fillMissing to the Book
+// fields they are stored in, including the ones normalizeBook derives.
+var autoFillFields = map[string][]string{
+	"authors":        {"Author", "Authors"},
+	"isbn_10":        {"ISBN10", "ISBN13"},
+	"isbn_13":        {"ISBN10", "ISBN13"},
+	"publisher":      {"Publisher"},
+	"published_date": {"PublishedDate"},
+	"page_count":     {"PageCount"},
+	"language":       {"Language"},
+	"categories":     {"Categories"},
+	"description":    {"Description"},
+	"thumbnail_url":  {"ThumbnailURL"},
+	"external_id":    {"ExternalSource", "ExternalID"},
+}
+
+// fillMissing copies the fields of src into the empty fields of b and
+// returns the JSON names of the fields it filled. The title is never changed.
+func fillMissing(b *Book, src Book) []string {
+	var filled []string
+	take := func(field string, missing, present bool, set func()) {
+		if missing && present {
+			set()
+			filled = append(filled, field)
+		}
+	}
+	take("authors", len(b.Authors) == 0, len(src.Authors) > 0, func() { b.Authors, b.Author = src.Authors, src.Author })
+	if b.ISBN10 == "" && b.ISBN13 == "" {
+		take("isbn_10", true, src.ISBN10 != "", func() { b.ISBN10 = src.ISBN10 })
+		take("isbn_13", true, src.ISBN13 != "", func() { b.ISBN13 = src.ISBN13 })
+	}
+	take("publisher", b.Publisher == "", src.Publisher != "", func() { b.Publisher = src.Publisher })
+	take("published_date", b.PublishedDate == "", src.PublishedDate != "", func() { b.PublishedDate = src.PublishedDate })
+	take("page_count", b.PageCount == 0, src.PageCount > 0, func() { b.PageCount = src.PageCount })
+	take("language", b.Language == "", src.Language != "", func() { b.Language = src.Language })
+	take("categories", len(b.Categories) == 0, len(src.Categories) > 0, func() { b.Categories = src.Categories })
+	take("description", b.Description == "", src.Description != "", func() { b.Description = src.Description })
+	take("thumbnail_url", b.ThumbnailURL == "", src.ThumbnailURL != "", func() { b.ThumbnailURL = src.ThumbnailURL })
+	take("external_id", b.ExternalID == "", src.ExternalID != "", func() {
+		b.ExternalSource, b.ExternalID = src.ExternalSource, src.ExternalID
+	})
+	return filled
+}
+
+//
+// ─────────────────────────── HELPERS ───────────────────────────
+//
+
+// rateLimiter spaces out calls so that at most one happens per interval.
+type rateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time // Earliest time the next call may start
+}
+
+// wait blocks until the caller may proceed or ctx is done.
+// A cancelled caller still uses up its slot.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	start := now
+	if l.next.After(now) {
+		start = l.next
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+	return sleepContext(ctx, start.Sub(now))
+}
+
+// newJobID returns a random 16-character hexadecimal job ID.
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCatalog is a GoogleBooksUsecase that answers searches from a fixed
+// list of volumes, matching by ISBN or by title words.
+type mockCatalog struct {
+	volumes  []GoogleBook
+	err      error
+	rejected string // Title whose searches fail with ErrUpstreamBadRequest
+	onSearch func() // Called during every search, e.g. to edit books meanwhile
+	searches []SearchParams
+}
+
+func (m *mockCatalog) Search(ctx context.Context, p SearchParams) (*SearchResult, error) {
+	m.searches = append(m.searches, p)
+	if m.onSearch != nil {
+		m.onSearch()
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.rejected != "" && p.Title == m.rejected {
+		return nil, NewError(ErrUpstreamBadRequest, "google books rejected the request")
+	}
+	res := &SearchResult{Items: []GoogleBook{}}
+	for _, v := range m.volumes {
+		if p.ISBN != "" && sharesISBN(volumeISBNs(&v), []string{p.ISBN}) ||
+			p.ISBN == "" && titleSimilarity(p.Title, v.Title) > 0 {
+			res.Items = append(res.Items, v)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockCatalog) GetVolume(context.Context, string) (*GoogleBook, error) {
+	return nil, ErrVolumeNotFound
+}
+
+// cleanCode is a Google Books volume used by the enrichment tests.
+var cleanCode = GoogleBook{
+	ID:                  "hjEFCAAAQBAJ",
+	Title:               "Clean Code: A Handbook of Agile Software Craftsmanship",
+	Authors:             []string{"Robert C. Martin"},
+	Author:              "Robert C. Martin",
+	Publisher:           "Pearson Education",
+	PublishedDate:       "2008-08-01",
+	PageCount:           464,
+	IndustryIdentifiers: []IndustryIdentifier{{Type: "ISBN_13", Identifier: "9780132350884"}},
+	ThumbnailURL:        "https://books.google.com/clean-code.jpg",
+}
+
+// TestMatchConfidence verifies the scoring of catalogue matches.
+func TestMatchConfidence(t *testing.T) {
+	cases := []struct {
+		book Book
+		want float64
+	}{
+		{Book{Title: "Clean Code", Author: "Robert C. Martin"}, 0.94},
+		{Book{Title: "clean code", Author: "Bob Martin"}, 0.86},
+		{Book{Title: "Clean Code"}, 0.74},
+		{Book{Title: "Clean Architecture", Author: "Robert C. Martin"}, 0.47},
+		{Book{Title: "Whatever", ISBN13: "9780132350884"}, 1},
+		{Book{Title: "Clean Code", Author: "Robert C. Martin", ISBN13: "9780134494166"}, 0},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, matchConfidence(&c.book, &cleanCode), "%+v", c.book)
+	}
+}
+
+// TestEnricher_EnrichPending verifies that confident matches fill in only the
+// missing fields, that every attempt is recorded so books are not looked up
+// again, and that complete books are left alone.
+func TestEnricher_EnrichPending(t *testing.T) {
+	repo := &mockBookRepo{books: []Book{
+		{ID: 1, WishlistID: 1, Title: "Clean Code", Author: "Robert C. Martin", Authors: []string{"Robert C. Martin"}, PageCount: 431},
+		{ID: 2, WishlistID: 1, Title: "Clean Architecture", Author: "Someone Else", Authors: []string{"Someone Else"}},
+		{ID: 3, WishlistID: 2, Title: "Complete", ISBN13: "9780306406157", ThumbnailURL: "https://example.com/c.jpg"},
+	}}
+	google := &mockCatalog{volumes: []GoogleBook{cleanCode}}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := NewEnricher(repo, newOwner(), google, EnrichmentOptions{LookupInterval: time.Nanosecond, Now: func() time.Time { return now }})
+
+	stats, err := e.EnrichPending(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, EnrichStats{Processed: 2, Enriched: 1, Skipped: 1}, stats)
+
+	b := repo.books[0]
+	assert.Equal(t, "Clean Code", b.Title, "the title is never replaced")
+	assert.Equal(t, 431, b.PageCount, "populated fields are never overwritten")
+	assert.Equal(t, "9780132350884", b.ISBN13)
+	assert.Equal(t, "0132350882", b.ISBN10)
+	assert.Equal(t, "https://books.google.com/clean-code.jpg", b.ThumbnailURL)
+	assert.Equal(t, "Pearson Education", b.Publisher)
+	assert.Equal(t, SourceGoogleBooks, b.ExternalSource)
+	assert.Equal(t, 0.94, b.EnrichmentConfidence)
+	assert.Equal(t, []string{"isbn_13", "publisher", "published_date", "thumbnail_url", "external_id"}, b.AutoFilled)
+	assert.Equal(t, now, *b.EnrichedAt)
+
+	skipped := repo.books[1]
+	assert.Empty(t, skipped.ISBN13)
+	assert.Empty(t, skipped.AutoFilled)
+	assert.NotNil(t, skipped.EnrichedAt)
+	assert.Equal(t, 0.07, skipped.EnrichmentConfidence)
+
+	stats, err = e.EnrichPending(t.Context())
+	assert.NoError(t, err)
+	assert.Zero(t, stats.Processed)
+	assert.Len(t, google.searches, 2)
+	assert.Equal(t, SearchParams{Title: "Clean Code", Author: "Robert C. Martin", MaxResults: 5, PrintType: "books"}, google.searches[0])
+}
+
+// TestEnricher_UpstreamError verifies that Google Books failures stop the
+// batch without marking books as enriched.
+func TestEnricher_UpstreamError(t *testing.T) {
+	repo := &mockBookRepo{books: []Book{
+		{ID: 1, WishlistID: 1, Title: "Clean Code"},
+		{ID: 2, WishlistID: 1, Title: "Refactoring"},
+	}}
+	google := &mockCatalog{err: NewError(ErrUpstreamQuota, "google books quota exceeded, try again later")}
+	e := NewEnricher(repo, newOwner(), google, EnrichmentOptions{LookupInterval: time.Nanosecond})
+
+	stats, err := e.EnrichPending(t.Context())
+	assert.ErrorIs(t, err, ErrUpstreamQuota)
+	assert.Zero(t, stats.Processed)
+	assert.Len(t, google.searches, 1)
+	assert.Nil(t, repo.books[0].EnrichedAt)
+}
+
+// TestEnricher_ConcurrentEdit verifies that edits made while a book is being
+// looked up are kept, and that books deleted meanwhile are skipped.
+func TestEnricher_ConcurrentEdit(t *testing.T) {
+	repo := &mockBookRepo{books: []Book{
+		{ID: 1, WishlistID: 1, Title: "Clean Code", Author: "Robert C. Martin", Authors: []string{"Robert C. Martin"}},
+		{ID: 2, WishlistID: 1, Title: "Clean Code", Author: "Robert C. Martin", Authors: []string{"Robert C. Martin"}},
+	}}
+	google := &mockCatalog{volumes: []GoogleBook{cleanCode}, onSearch: func() {
+		if len(repo.books) == 2 {
+			repo.books[0].Notes = "Gift for Sam"
+			repo.books[0].Publisher = "Prentice Hall"
+			repo.books = repo.books[:1]
+		}
+	}}
+	e := NewEnricher(repo, newOwner(), google, EnrichmentOptions{LookupInterval: time.Nanosecond})
+
+	stats, err := e.EnrichPending(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, EnrichStats{Processed: 2, Enriched: 1, Skipped: 1}, stats)
+	assert.Len(t, repo.books, 1)
+
+	b := repo.books[0]
+	assert.Equal(t, "Gift for Sam", b.Notes)
+	assert.Equal(t, "Prentice Hall", b.Publisher, "fields filled in meanwhile are kept")
+	assert.Equal(t, "9780132350884", b.ISBN13)
+	assert.Equal(t, []string{"isbn_13", "published_date", "page_count", "thumbnail_url", "external_id"}, b.AutoFilled)
+}
+
+// TestEnricher_RejectedSearch verifies that a search Google Books rejects
+// counts as a lookup without a match, so later books are still enriched and
+// the rejected book is not looked up again.
+func TestEnricher_RejectedSearch(t *testing.T) {
+	repo := &mockBookRepo{books: []Book{
+		{ID: 1, WishlistID: 1, Title: "Rejected"},
+		{ID: 2, WishlistID: 1, Title: "Clean Code", Author: "Robert C. Martin", Authors: []string{"Robert C. Martin"}},
+	}}
+	google := &mockCatalog{volumes: []GoogleBook{cleanCode}, rejected: "Rejected"}
+	e := NewEnricher(repo, newOwner(), google, EnrichmentOptions{LookupInterval: time.Nanosecond})
+
+	stats, err := e.EnrichPending(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, EnrichStats{Processed: 2, Enriched: 1, Skipped: 1}, stats)
+	assert.NotNil(t, repo.books[0].EnrichedAt)
+	assert.Zero(t, repo.books[0].EnrichmentConfidence)
+	assert.Equal(t, "9780132350884", repo.books[1].ISBN13)
+
+	stats, err = e.EnrichPending(t.Context())
+	assert.NoError(t, err)
+	assert.Zero(t, stats.Processed)
+}
+
+// TestEnricher_Jobs verifies on-demand jobs: ownership checks, progress
+// polling and that jobs are private to the user who started them.
+func TestEnricher_Jobs(t *testing.T) {
+	enriched := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &mockBookRepo{books: []Book{
+		{ID: 1, WishlistID: 1, Title: "Clean Code", Author: "Robert C. Martin", Authors: []string{"Robert C. Martin"}, EnrichedAt: &enriched},
+		{ID: 2, WishlistID: 1, Title: "Done", ISBN10: "0306406152", ThumbnailURL: "https://example.com/d.jpg"},
+	}}
+	e := NewEnricher(repo, newOwner(), &mockCatalog{volumes: []GoogleBook{cleanCode}}, EnrichmentOptions{LookupInterval: time.Nanosecond})
+
+	_, err := e.Enrich(t.Context(), 1, 2)
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+	_, err = e.Enrich(t.Context(), 1, 9)
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+
+	job, err := e.Enrich(t.Context(), 1, 1)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, job.ID)
+	assert.Equal(t, 1, job.Total, "books enriched before are retried on demand")
+
+	assert.Eventually(t, func() bool {
+		job, err = e.Job(t.Context(), 1, job.ID)
+		return err == nil && job.Status != JobRunning
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, JobDone, job.Status)
+	assert.Equal(t, EnrichStats{Processed: 1, Enriched: 1}, job.EnrichStats)
+	assert.NotNil(t, job.FinishedAt)
+	assert.Equal(t, "9780132350884", repo.books[0].ISBN13)
+
+	_, err = e.Job(t.Context(), 2, job.ID)
+	assert.ErrorIs(t, err, ErrEnrichJobNotFound)
+	_, err = e.Job(t.Context(), 1, "unknown")
+	assert.ErrorIs(t, err, ErrEnrichJobNotFound)
+}
+
+// TestEnricher_RateLimit verifies that lookups are spaced by LookupInterval.
+func TestEnricher_RateLimit(t *testing.T) {
+	repo := &mockBookRepo{books: []Book{
+		{ID: 1, WishlistID: 1, Title: "A"},
+		{ID: 2, WishlistID: 1, Title: "B"},
+		{ID: 3, WishlistID: 1, Title: "C"},
+	}}
+	e := NewEnricher(repo, newOwner(), &mockCatalog{}, EnrichmentOptions{LookupInterval: 30 * time.Millisecond})
+
+	start := time.Now()
+	stats, err := e.EnrichPending(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.Processed)
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+}
+
+// TestEnricher_Cancel verifies that a cancelled context stops the worker
+// without marking the remaining books as attempted.
+func TestEnricher_Cancel(t *testing.T) {
+	repo := &mockBookRepo{books: []Book{{ID: 1, WishlistID: 1, Title: "Clean Code"}}}
+	google := &mockCatalog{volumes: []GoogleBook{cleanCode}}
+	e := NewEnricher(repo, newOwner(), google, EnrichmentOptions{Interval: time.Hour, LookupInterval: time.Hour})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err := e.EnrichPending(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, google.searches)
+	assert.Nil(t, repo.books[0].EnrichedAt)
+
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx, nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after its context was cancelled")
+	}
+}
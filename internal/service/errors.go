@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+//
+// ─────────────────────────── ERROR KINDS ───────────────────────────
+//
+
+// Sentinel error kinds shared by services and repositories.
+// Callers should classify errors with errors.Is against these values;
+// the handler layer maps each kind to an HTTP status code. Work stopped by a
+// cancelled or expired context returns the context's error unchanged.
+var (
+	// ErrNotFound is returned when a requested entity does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is returned when an operation would violate a uniqueness rule.
+	ErrConflict = errors.New("conflict")
+
+	// ErrForbidden is returned when the caller may not access an entity.
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrValidation is returned when input fails business validation.
+	ErrValidation = errors.New("validation failed")
+
+	// ErrUpstreamQuota is returned when an external API rejects a call because
+	// a rate limit or quota was exceeded.
+	ErrUpstreamQuota = errors.New("upstream quota exceeded")
+
+	// ErrUpstreamBadRequest is returned when an external API rejects the request
+	// itself (malformed query, invalid API key).
+	ErrUpstreamBadRequest = errors.New("upstream rejected request")
+
+	// ErrUpstreamUnavailable is returned when an external API cannot be reached,
+	// fails with a server error or returns a malformed response.
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+)
+
+// Error is a domain error that carries one of the sentinel kinds above
+// together with a client-facing message.
+type Error struct {
+	Kind error  // One of the sentinel kinds above
+	Msg  string // Human-readable message
+	Err  error  // Optional underlying cause; logged, never shown to clients
+}
+
+// Error returns the client-facing message.
+func (e *Error) Error() string { return e.Msg }
+
+// Unwrap exposes the kind (and cause, if any) so errors.Is(err, ErrNotFound)
+// and friends work.
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// NewError creates a domain error of the given kind.
+func NewError(kind error, msg string) error {
+	return &Error{Kind: kind, Msg: msg}
+}
+
+// isContextError reports whether err comes from a cancelled context or an
+// expired deadline rather than from the operation itself.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// validationErrorf creates an ErrValidation error with a formatted message.
+func validationErrorf(format string, args ...any) error {
+	return NewError(ErrValidation, fmt.Sprintf(format, args...))
+}
+
+//
+// ─────────────────────────── DOMAIN ERRORS ───────────────────────────
+//
+
+// Predefined business-level errors.
+var (
+	// ErrInvalidInput is returned when username or password are empty.
+	ErrInvalidInput = NewError(ErrValidation, "username and password cannot be empty")
+
+	// ErrPasswordTooLong is returned when a password exceeds bcrypt's 72-byte input limit.
+	ErrPasswordTooLong = NewError(ErrValidation, "password cannot exceed 72 bytes")
+
+	// ErrInvalidCredentials is returned when a login attempt does not match a user.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+
+	// ErrUsernameTaken is returned when registering a username that already exists.
+	ErrUsernameTaken = NewError(ErrConflict, "username already exists")
+
+	// ErrUserNotFound is returned when a user ID does not match any user.
+	ErrUserNotFound = NewError(ErrNotFound, "user not found")
+
+	// ErrWishlistNotFound is returned when a wishlist ID does not exist or the
+	// wishlist belongs to another user, so that other users' IDs are not revealed.
+	ErrWishlistNotFound = NewError(ErrNotFound, "wishlist not found")
+
+	// ErrBookNotFound is returned when a book ID does not exist in the wishlist.
+	ErrBookNotFound = NewError(ErrNotFound, "book not found")
+
+	// ErrVolumeNotFound is returned when Google Books has no volume with the given ID.
+	ErrVolumeNotFound = NewError(ErrNotFound, "google books volume not found")
+
+	// ErrDuplicateBook is returned when a wishlist already holds the book being added.
+	ErrDuplicateBook = NewError(ErrConflict, "book already exists in this wishlist")
+
+	// ErrEnrichJobNotFound is returned when an enrichment job does not exist
+	// or was started by another user.
+	ErrEnrichJobNotFound = NewError(ErrNotFound, "enrichment job not found")
+)
@@ -1,68 +1,444 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 //
 // ─────────────────────────── GOOGLE BOOKS SERVICE ───────────────────────────
 //
 
-// GoogleBook represents a simplified result from Google Books API.
+// GoogleBook represents a Google Books volume. It is also the common shape of
+// results from the other catalogue providers (see CatalogUsecase).
+// Author repeats the first entry of Authors for older clients.
 type GoogleBook struct {
-	Title  string `json:"title"`
-	Author string `json:"author"`
+	ID                  string               `json:"id"                        example:"zyTCAlFPjgYC"`
+	Title               string               `json:"title"                     example:"The Go Programming Language"`
+	Author              string               `json:"author"                    example:"Alan A. A. Donovan"`
+	Authors             []string             `json:"authors"                   example:"Alan A. A. Donovan,Brian W. Kernighan"`
+	Publisher           string               `json:"publisher,omitempty"       example:"Addison-Wesley Professional"`
+	PublishedDate       string               `json:"published_date,omitempty"  example:"2015-11-16"`
+	Description         string               `json:"description,omitempty"     example:"The authoritative resource to writing clear and idiomatic Go."`
+	PageCount           int                  `json:"page_count,omitempty"      example:"380"`
+	Language            string               `json:"language,omitempty"        example:"en"`
+	Categories          []string             `json:"categories,omitempty"      example:"Computers"`
+	IndustryIdentifiers []IndustryIdentifier `json:"industry_identifiers"`
+	ThumbnailURL        string               `json:"thumbnail_url,omitempty"   example:"https://books.google.com/books/content?id=zyTCAlFPjgYC&printsec=frontcover&img=1&zoom=1"`
+
+	// Set by catalogue searches: the provider the volume (and its ID) comes from and,
+	// for aggregate searches, the provider of each populated field keyed by JSON name.
+	Provider     string            `json:"provider,omitempty"      example:"google_books"`
+	FieldSources map[string]string `json:"field_sources,omitempty" example:"title:google_books,page_count:open_library"`
+
+	// All known ISBN-13s, including other editions, used to merge providers.
+	// Exported so that it survives the persistent cache's JSON round trip.
+	ISBNs []string `json:"isbns,omitempty" example:"9780134190440"`
+}
+
+// IndustryIdentifier is a standard identifier of a volume, such as an ISBN.
+type IndustryIdentifier struct {
+	Type       string `json:"type"       example:"ISBN_13"` // ISBN_10, ISBN_13, ISSN or OTHER
+	Identifier string `json:"identifier" example:"9780134190440"`
+}
+
+// googleVolume is the subset of a Google Books volume resource the API uses.
+type googleVolume struct {
+	ID         string `json:"id"`
+	VolumeInfo struct {
+		Title               string               `json:"title"`
+		Authors             []string             `json:"authors"`
+		Publisher           string               `json:"publisher"`
+		PublishedDate       string               `json:"publishedDate"`
+		Description         string               `json:"description"`
+		PageCount           int                  `json:"pageCount"`
+		Language            string               `json:"language"`
+		Categories          []string             `json:"categories"`
+		IndustryIdentifiers []IndustryIdentifier `json:"industryIdentifiers"`
+		ImageLinks          struct {
+			SmallThumbnail string `json:"smallThumbnail"`
+			Thumbnail      string `json:"thumbnail"`
+		} `json:"imageLinks"`
+	} `json:"volumeInfo"`
+}
+
+// toGoogleBook converts a volume resource into a GoogleBook.
+// Lists are never nil and thumbnail links are upgraded to https.
+func (v *googleVolume) toGoogleBook() GoogleBook {
+	info := v.VolumeInfo
+	b := GoogleBook{
+		ID:                  v.ID,
+		Title:               info.Title,
+		Authors:             info.Authors,
+		Publisher:           info.Publisher,
+		PublishedDate:       info.PublishedDate,
+		Description:         info.Description,
+		PageCount:           info.PageCount,
+		Language:            info.Language,
+		Categories:          info.Categories,
+		IndustryIdentifiers: info.IndustryIdentifiers,
+		ThumbnailURL:        info.ImageLinks.Thumbnail,
+	}
+	if b.Authors == nil {
+		b.Authors = []string{}
+	}
+	if len(b.Authors) > 0 {
+		b.Author = b.Authors[0]
+	}
+	if b.IndustryIdentifiers == nil {
+		b.IndustryIdentifiers = []IndustryIdentifier{}
+	}
+	if b.ThumbnailURL == "" {
+		b.ThumbnailURL = info.ImageLinks.SmallThumbnail
+	}
+	if strings.HasPrefix(b.ThumbnailURL, "http://") {
+		b.ThumbnailURL = "https://" + strings.TrimPrefix(b.ThumbnailURL, "http://")
+	}
+	return b
+}
+
+// ToBook maps the volume into a wishlist book with full metadata and its
+// Google volume ID as external reference. Identifiers and dates that would
+// fail book validation are dropped or truncated rather than rejected.
+func (b GoogleBook) ToBook() Book {
+	book := Book{
+		Title:          b.Title,
+		Authors:        append([]string(nil), b.Authors...),
+		Publisher:      b.Publisher,
+		PublishedDate:  coercePublishedDate(b.PublishedDate),
+		PageCount:      b.PageCount,
+		Language:       b.Language,
+		Categories:     append([]string(nil), b.Categories...),
+		Description:    b.Description,
+		ThumbnailURL:   b.ThumbnailURL,
+		ExternalSource: SourceGoogleBooks,
+		ExternalID:     b.ID,
+	}
+	for _, id := range b.IndustryIdentifiers {
+		isbn := NormalizeISBN(id.Identifier)
+		switch {
+		case id.Type == "ISBN_10" && ValidISBN10(isbn):
+			book.ISBN10 = isbn
+		case id.Type == "ISBN_13" && ValidISBN13(isbn):
+			book.ISBN13 = isbn
+		}
+	}
+	if book.ISBN10 != "" && book.ISBN13 != "" && ISBN10To13(book.ISBN10) != book.ISBN13 {
+		book.ISBN10 = "" // inconsistent upstream data; keep the canonical form
+	}
+	return book
+}
+
+// coercePublishedDate reduces a catalogue date to the YYYY[-MM[-DD]] form
+// accepted for books, returning "" when no valid prefix exists.
+func coercePublishedDate(s string) string {
+	s = strings.TrimSpace(s)
+	for _, n := range []int{10, 7, 4} {
+		if len(s) >= n && validPublishedDate(s[:n]) {
+			return s[:n]
+		}
+	}
+	return ""
+}
+
+// Default values applied by NewGoogleBooksService when the options leave them empty.
+const (
+	DefaultGoogleBooksBaseURL   = "https://www.googleapis.com"
+	DefaultGoogleBooksTimeout   = 10 * time.Second
+	DefaultGoogleBooksUserAgent = "wishlist-api/1.0"
+)
+
+// GoogleBooksOptions configures the Google Books client.
+type GoogleBooksOptions struct {
+	BaseURL   string        // API root without the /books/v1 path (default: DefaultGoogleBooksBaseURL)
+	APIKey    string        // Optional API key, sent as the "key" query parameter
+	Client    *http.Client  // HTTP client to use (default: a new client with Timeout)
+	Timeout   time.Duration // Request timeout when Client is nil (default: DefaultGoogleBooksTimeout)
+	UserAgent string        // User-Agent header (default: DefaultGoogleBooksUserAgent)
+
+	MaxRetries       int                                        // Retries after a 429, 5xx or network error (default: DefaultGoogleBooksMaxRetries; negative disables)
+	RetryBaseDelay   time.Duration                              // Wait before the first retry, doubled for each next one (default: DefaultGoogleBooksRetryBaseDelay)
+	RetryMaxDelay    time.Duration                              // Longest single wait, including Retry-After (default: DefaultGoogleBooksRetryMaxDelay)
+	BreakerThreshold int                                        // Consecutive failed requests that open the circuit (default: DefaultGoogleBooksBreakerThreshold; negative disables)
+	BreakerCooldown  time.Duration                              // How long the circuit stays open (default: DefaultGoogleBooksBreakerCooldown)
+	Sleep            func(context.Context, time.Duration) error // Waits between retries unless ctx is done first (default: sleepContext)
 }
 
 // googleBooksService implements the GoogleBooksUsecase interface.
 // It provides methods to search for books using the Google Books API.
-type googleBooksService struct{}
+type googleBooksService struct {
+	baseURL   string
+	apiKey    string
+	client    *http.Client
+	userAgent string
 
-// NewGoogleBooksService creates a new instance of googleBooksService.
-func NewGoogleBooksService() GoogleBooksUsecase {
-	return &googleBooksService{}
+	maxRetries     int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+	sleep          func(context.Context, time.Duration) error
+	breaker        *circuitBreaker // nil when disabled
 }
 
-// Search queries the Google Books API with the given search term
-// and returns a simplified list of books (title and first author).
-func (g *googleBooksService) Search(query string) ([]GoogleBook, error) {
-	url := fmt.Sprintf("https://www.googleapis.com/books/v1/volumes?q=%s", query)
+// NewGoogleBooksService creates a Google Books client from opts,
+// applying the defaults above to empty fields.
+func NewGoogleBooksService(opts GoogleBooksOptions) GoogleBooksUsecase {
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultGoogleBooksBaseURL
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultGoogleBooksTimeout
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: opts.Timeout}
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultGoogleBooksUserAgent
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultGoogleBooksMaxRetries
+	}
+	if opts.RetryBaseDelay <= 0 {
+		opts.RetryBaseDelay = DefaultGoogleBooksRetryBaseDelay
+	}
+	if opts.RetryMaxDelay <= 0 {
+		opts.RetryMaxDelay = DefaultGoogleBooksRetryMaxDelay
+	}
+	if opts.BreakerThreshold == 0 {
+		opts.BreakerThreshold = DefaultGoogleBooksBreakerThreshold
+	}
+	if opts.BreakerCooldown <= 0 {
+		opts.BreakerCooldown = DefaultGoogleBooksBreakerCooldown
+	}
+	if opts.Sleep == nil {
+		opts.Sleep = sleepContext
+	}
+	g := &googleBooksService{
+		baseURL:        strings.TrimRight(opts.BaseURL, "/"),
+		apiKey:         opts.APIKey,
+		client:         opts.Client,
+		userAgent:      opts.UserAgent,
+		maxRetries:     max(opts.MaxRetries, 0),
+		retryBaseDelay: opts.RetryBaseDelay,
+		retryMaxDelay:  opts.RetryMaxDelay,
+		sleep:          opts.Sleep,
+	}
+	if opts.BreakerThreshold > 0 {
+		g.breaker = &circuitBreaker{threshold: opts.BreakerThreshold, cooldown: opts.BreakerCooldown, now: time.Now}
+	}
+	return g
+}
 
-	// Send HTTP request to Google Books API
-	resp, err := http.Get(url)
-	if err != nil {
+// Search queries the Google Books API with the given parameters and returns
+// one page of simplified results together with the total match count.
+//
+// Upstream failures are reported as ErrUpstreamQuota (rate limit or quota),
+// ErrUpstreamBadRequest (request rejected) or ErrUpstreamUnavailable
+// (unreachable, server error or malformed response); GetVolume uses the same errors.
+func (g *googleBooksService) Search(ctx context.Context, p SearchParams) (*SearchResult, error) {
+	if err := p.normalize(); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// Parse response JSON into a simplified structure
+	params := url.Values{
+		"q":          {p.googleQuery()},
+		"startIndex": {strconv.Itoa(p.StartIndex)},
+		"maxResults": {strconv.Itoa(p.MaxResults)},
+	}
+	if p.OrderBy != "" {
+		params.Set("orderBy", p.OrderBy)
+	}
+	if p.PrintType != "" {
+		params.Set("printType", p.PrintType)
+	}
+	if p.Language != "" {
+		params.Set("langRestrict", p.Language)
+	}
+
 	var data struct {
-		Items []struct {
-			VolumeInfo struct {
-				Title   string   `json:"title"`
-				Authors []string `json:"authors"`
-			} `json:"volumeInfo"`
-		} `json:"items"`
+		TotalItems int            `json:"totalItems"`
+		Items      []googleVolume `json:"items"`
+	}
+	if err := g.getJSON(ctx, "/books/v1/volumes", params, &data); err != nil {
+		return nil, err
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	books := make([]GoogleBook, 0, len(data.Items))
+	for i := range data.Items {
+		books = append(books, data.Items[i].toGoogleBook())
+	}
+
+	res := &SearchResult{
+		TotalItems: data.TotalItems,
+		StartIndex: p.StartIndex,
+		MaxResults: p.MaxResults,
+		Items:      books,
+	}
+	// Google's totalItems is an estimate; an empty page also means the end.
+	if next := p.StartIndex + len(books); len(books) > 0 && next < data.TotalItems {
+		res.NextStartIndex = next
+		res.HasMore = true
+	}
+	return res, nil
+}
+
+// GetVolume fetches the details of a single volume by its Google Books ID.
+// Returns ErrVolumeNotFound if Google does not know the ID.
+func (g *googleBooksService) GetVolume(ctx context.Context, volumeID string) (*GoogleBook, error) {
+	volumeID = strings.TrimSpace(volumeID)
+	if !validVolumeID(volumeID) {
+		return nil, validationErrorf("invalid volume ID %q", volumeID)
+	}
+	var v googleVolume
+	if err := g.getJSON(ctx, "/books/v1/volumes/"+volumeID, url.Values{}, &v); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrVolumeNotFound
+		}
 		return nil, err
 	}
+	book := v.toGoogleBook()
+	return &book, nil
+}
 
-	// Extract relevant data: title and first author
-	var books []GoogleBook
-	for _, item := range data.Items {
-		author := ""
-		if len(item.VolumeInfo.Authors) > 0 {
-			author = item.VolumeInfo.Authors[0]
+// validVolumeID reports whether id looks like a Google Books volume ID
+// (letters, digits, "-" and "_"), which also keeps it safe to use in a URL path.
+func validVolumeID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
 		}
-		books = append(books, GoogleBook{
-			Title:  item.VolumeInfo.Title,
-			Author: author,
-		})
 	}
+	return true
+}
+
+// getJSON sends a GET request for path with the given query parameters
+// (plus the API key) and decodes a 200 response into dst.
+//
+// Rate limits, server errors and network errors are retried with jittered
+// exponential backoff, waiting for Retry-After when Google sends it; a
+// Retry-After longer than the configured maximum delay is not waited for.
+// Requests fail fast with ErrCircuitOpen while the circuit breaker is open.
+// When ctx is done the request stops, without retrying, with ctx's error;
+// such requests do not count towards the circuit breaker.
+func (g *googleBooksService) getJSON(ctx context.Context, path string, params url.Values, dst any) error {
+	if g.apiKey != "" {
+		params.Set("key", g.apiKey)
+	}
+	rawURL := g.baseURL + path + "?" + params.Encode()
 
-	return books, nil
+	generation, err := g.breaker.allow()
+	if err != nil {
+		return err
+	}
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		var wait time.Duration
+		retry, wait, err = g.fetchJSON(ctx, rawURL, dst)
+		if err == nil || !retry || attempt >= g.maxRetries {
+			break
+		}
+		if wait == 0 {
+			wait = backoff(attempt, g.retryBaseDelay, g.retryMaxDelay)
+		} else if wait > g.retryMaxDelay {
+			break
+		}
+		if serr := g.sleep(ctx, wait); serr != nil {
+			err = serr
+			break
+		}
+	}
+	if isContextError(err) {
+		g.breaker.release(generation)
+		return err
+	}
+	g.breaker.record(generation, errors.Is(err, ErrUpstreamUnavailable))
+	return err
+}
+
+// fetchJSON performs a single attempt of getJSON. On failure it also reports
+// whether the request may be retried and the Retry-After delay, if any.
+func (g *googleBooksService) fetchJSON(ctx context.Context, rawURL string, dst any) (retry bool, wait time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return false, 0, err
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	// Send HTTP request to Google Books API
+	resp, err := g.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, 0, ctx.Err()
+		}
+		// Drop the *url.Error wrapper: its message contains the API key.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return true, 0, &Error{Kind: ErrUpstreamUnavailable, Msg: "google books is unreachable", Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return retryableStatus(resp.StatusCode), retryAfter(resp.Header, time.Now()), googleStatusError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return false, 0, &Error{Kind: ErrUpstreamUnavailable, Msg: "google books returned a malformed response", Err: err}
+	}
+	return false, 0, nil
+}
+
+// googleQuotaReasons are the error reasons Google uses for rate limits and quotas.
+var googleQuotaReasons = map[string]bool{
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+	"dailyLimitExceeded":    true,
+	"quotaExceeded":         true,
+}
+
+// googleStatusError converts a non-200 Google Books response into a typed error.
+// Google reports quota problems as 429 or as 403 with a quota reason, so the
+// error body is inspected before falling back to the status code.
+func googleStatusError(resp *http.Response) error {
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body)
+
+	quota := resp.StatusCode == http.StatusTooManyRequests
+	for _, e := range body.Error.Errors {
+		quota = quota || googleQuotaReasons[e.Reason]
+	}
+
+	detail := fmt.Errorf("status %d: %s", resp.StatusCode, body.Error.Message)
+	switch {
+	case quota:
+		return &Error{Kind: ErrUpstreamQuota, Msg: "google books quota exceeded, try again later", Err: detail}
+	case resp.StatusCode == http.StatusNotFound:
+		return &Error{Kind: ErrNotFound, Msg: "not found in google books", Err: detail}
+	case resp.StatusCode >= 500:
+		return &Error{Kind: ErrUpstreamUnavailable, Msg: "google books is unavailable", Err: detail}
+	default:
+		msg := "google books rejected the request"
+		if body.Error.Message != "" {
+			msg += ": " + body.Error.Message
+		}
+		return &Error{Kind: ErrUpstreamBadRequest, Msg: msg, Err: detail}
+	}
 }
@@ -0,0 +1,330 @@
+package service_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// volumesJSON is a trimmed Google Books /volumes response.
+const volumesJSON = `{
+	"totalItems": 2,
+	"items": [
+		{"volumeInfo": {"title": "The Go Programming Language", "authors": ["Alan Donovan", "Brian Kernighan"]}},
+		{"volumeInfo": {"title": "Anonymous Book"}}
+	]
+}`
+
+// TestGoogleBooks_Search verifies the request sent to the configured base URL
+// (path, query, API key, User-Agent) and the parsing of the response.
+func TestGoogleBooks_Search(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(volumesJSON))
+	}))
+	defer srv.Close()
+
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{
+		BaseURL:   srv.URL + "/",
+		APIKey:    "secret",
+		UserAgent: "wishlist-test",
+	})
+
+	res, err := svc.Search(t.Context(), service.SearchParams{Query: "golang"})
+	assert.NoError(t, err)
+	books := res.Items
+	assert.Equal(t, "/books/v1/volumes", got.URL.Path)
+	assert.Equal(t, "golang", got.URL.Query().Get("q"))
+	assert.Equal(t, "secret", got.URL.Query().Get("key"))
+	assert.Equal(t, "wishlist-test", got.Header.Get("User-Agent"))
+
+	assert.Len(t, books, 2)
+	assert.Equal(t, "The Go Programming Language", books[0].Title)
+	assert.Equal(t, "Alan Donovan", books[0].Author)
+	assert.Equal(t, []string{"Alan Donovan", "Brian Kernighan"}, books[0].Authors)
+	assert.Empty(t, books[1].Author)
+	assert.NotNil(t, books[1].Authors)
+	assert.NotNil(t, books[1].IndustryIdentifiers)
+}
+
+// TestGoogleBooks_Defaults verifies that no key is sent when none is
+// configured and that the default User-Agent is used.
+func TestGoogleBooks_Defaults(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL})
+
+	res, err := svc.Search(t.Context(), service.SearchParams{Query: "golang"})
+	assert.NoError(t, err)
+	assert.Empty(t, res.Items)
+	assert.NotNil(t, res.Items)
+	assert.False(t, got.URL.Query().Has("key"))
+	assert.Equal(t, service.DefaultGoogleBooksUserAgent, got.Header.Get("User-Agent"))
+}
+
+// TestGoogleBooks_ClientAndTimeout verifies that a supplied *http.Client is
+// used and that the timeout aborts slow upstream responses.
+func TestGoogleBooks_ClientAndTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL, Timeout: 50 * time.Millisecond, MaxRetries: -1})
+	start := time.Now()
+	_, err := svc.Search(t.Context(), service.SearchParams{Query: "slow"})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	used := false
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		used = true
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer fast.Close()
+
+	svc = service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: fast.URL, Client: client})
+	_, err = svc.Search(t.Context(), service.SearchParams{Query: "golang"})
+	assert.NoError(t, err)
+	assert.True(t, used)
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// TestGoogleBooks_QueryEncoding verifies that reserved characters in the
+// search term reach Google intact instead of splitting the query string.
+func TestGoogleBooks_QueryEncoding(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL, APIKey: "k"})
+	_, err := svc.Search(t.Context(), service.SearchParams{Query: "c++ & go #1 50%"})
+	assert.NoError(t, err)
+	assert.Equal(t, "c++ & go #1 50%", got.URL.Query().Get("q"))
+	assert.Equal(t, "k", got.URL.Query().Get("key"))
+
+	_, err = svc.Search(t.Context(), service.SearchParams{Query: "   "})
+	assert.ErrorIs(t, err, service.ErrValidation)
+}
+
+// TestGoogleBooks_UpstreamErrors verifies that upstream failures are
+// classified instead of being decoded as an empty result.
+func TestGoogleBooks_UpstreamErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"too many requests", http.StatusTooManyRequests, `{}`, service.ErrUpstreamQuota},
+		{"daily limit", http.StatusForbidden, `{"error":{"code":403,"message":"Daily Limit Exceeded","errors":[{"reason":"dailyLimitExceeded"}]}}`, service.ErrUpstreamQuota},
+		{"bad key", http.StatusBadRequest, `{"error":{"code":400,"message":"API key not valid","errors":[{"reason":"keyInvalid"}]}}`, service.ErrUpstreamBadRequest},
+		{"forbidden", http.StatusForbidden, `not json`, service.ErrUpstreamBadRequest},
+		{"server error", http.StatusInternalServerError, ``, service.ErrUpstreamUnavailable},
+		{"unavailable", http.StatusServiceUnavailable, ``, service.ErrUpstreamUnavailable},
+		{"malformed", http.StatusOK, `{"items":`, service.ErrUpstreamUnavailable},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.status)
+				w.Write([]byte(c.body))
+			}))
+			defer srv.Close()
+
+			svc := service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL, MaxRetries: -1})
+			res, err := svc.Search(t.Context(), service.SearchParams{Query: "golang"})
+			assert.ErrorIs(t, err, c.want)
+			assert.Nil(t, res)
+		})
+	}
+
+	// Unreachable host; the API key must not leak into the message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close()
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL, APIKey: "secret", MaxRetries: -1})
+	_, err := svc.Search(t.Context(), service.SearchParams{Query: "golang"})
+	assert.ErrorIs(t, err, service.ErrUpstreamUnavailable)
+	assert.NotContains(t, err.Error(), "secret")
+}
+
+// TestGoogleBooks_SearchParams verifies how pagination, ordering and the
+// field-qualified filters are translated into Google's query parameters.
+func TestGoogleBooks_SearchParams(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(volumesJSON))
+	}))
+	defer srv.Close()
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL})
+
+	res, err := svc.Search(t.Context(), service.SearchParams{
+		Query:      "programming",
+		Title:      "go language",
+		Author:     "Kernighan",
+		ISBN:       "978-0-13-419044-0",
+		Subject:    "Computers",
+		StartIndex: 0,
+		MaxResults: 2,
+		OrderBy:    "Newest",
+		PrintType:  "books",
+		Language:   "EN",
+	})
+	assert.NoError(t, err)
+	q := got.URL.Query()
+	assert.Equal(t, `programming intitle:"go language" inauthor:Kernighan isbn:9780134190440 subject:Computers`, q.Get("q"))
+	assert.Equal(t, "0", q.Get("startIndex"))
+	assert.Equal(t, "2", q.Get("maxResults"))
+	assert.Equal(t, "newest", q.Get("orderBy"))
+	assert.Equal(t, "books", q.Get("printType"))
+	assert.Equal(t, "en", q.Get("langRestrict"))
+
+	// Two of two results: no further page
+	assert.Equal(t, 2, res.TotalItems)
+	assert.False(t, res.HasMore)
+
+	// Defaults
+	res, err = svc.Search(t.Context(), service.SearchParams{Title: "go"})
+	assert.NoError(t, err)
+	q = got.URL.Query()
+	assert.Equal(t, "intitle:go", q.Get("q"))
+	assert.Equal(t, "10", q.Get("maxResults"))
+	assert.False(t, q.Has("orderBy"))
+	assert.False(t, q.Has("langRestrict"))
+	assert.Equal(t, service.DefaultSearchMaxResults, res.MaxResults)
+
+	invalid := []service.SearchParams{
+		{},
+		{Query: "go", StartIndex: -1},
+		{Query: "go", MaxResults: 41},
+		{Query: "go", OrderBy: "oldest"},
+		{Query: "go", PrintType: "comics"},
+		{Query: "go", Language: "english"},
+	}
+	for _, p := range invalid {
+		_, err := svc.Search(t.Context(), p)
+		assert.ErrorIs(t, err, service.ErrValidation, "%+v", p)
+	}
+}
+
+// TestGoogleBooks_NextPage verifies next-page information.
+func TestGoogleBooks_NextPage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"totalItems": 25, "items": [{"volumeInfo": {"title": "A"}}, {"volumeInfo": {"title": "B"}}]}`))
+	}))
+	defer srv.Close()
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL})
+
+	res, err := svc.Search(t.Context(), service.SearchParams{Query: "go", StartIndex: 10, MaxResults: 2})
+	assert.NoError(t, err)
+	assert.True(t, res.HasMore)
+	assert.Equal(t, 12, res.NextStartIndex)
+	assert.Equal(t, 10, res.StartIndex)
+
+	res, err = svc.Search(t.Context(), service.SearchParams{Query: "go", StartIndex: 23, MaxResults: 2})
+	assert.NoError(t, err)
+	assert.False(t, res.HasMore)
+}
+
+// volumeJSON is a trimmed Google Books volume resource.
+const volumeJSON = `{
+	"id": "zyTCAlFPjgYC",
+	"volumeInfo": {
+		"title": "The Go Programming Language",
+		"authors": ["Alan A. A. Donovan", "Brian W. Kernighan"],
+		"publisher": "Addison-Wesley Professional",
+		"publishedDate": "2015-11-16",
+		"description": "The authoritative resource.",
+		"industryIdentifiers": [
+			{"type": "ISBN_13", "identifier": "9780134190570"},
+			{"type": "ISBN_10", "identifier": "0134190572"}
+		],
+		"pageCount": 400,
+		"categories": ["Computers"],
+		"language": "en",
+		"imageLinks": {"thumbnail": "http://books.google.com/books/content?id=zyTCAlFPjgYC&img=1"}
+	}
+}`
+
+// TestGoogleBooks_GetVolume verifies that all volume details are mapped and
+// that unknown or malformed IDs are reported as such.
+func TestGoogleBooks_GetVolume(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if r.URL.Path != "/books/v1/volumes/zyTCAlFPjgYC" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":404,"message":"The volume ID could not be found."}}`))
+			return
+		}
+		w.Write([]byte(volumeJSON))
+	}))
+	defer srv.Close()
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL})
+
+	book, err := svc.GetVolume(t.Context(), "zyTCAlFPjgYC")
+	assert.NoError(t, err)
+	assert.Equal(t, "/books/v1/volumes/zyTCAlFPjgYC", path)
+	assert.Equal(t, "zyTCAlFPjgYC", book.ID)
+	assert.Equal(t, []string{"Alan A. A. Donovan", "Brian W. Kernighan"}, book.Authors)
+	assert.Equal(t, "Alan A. A. Donovan", book.Author)
+	assert.Equal(t, "Addison-Wesley Professional", book.Publisher)
+	assert.Equal(t, "2015-11-16", book.PublishedDate)
+	assert.Equal(t, 400, book.PageCount)
+	assert.Equal(t, "en", book.Language)
+	assert.Equal(t, []string{"Computers"}, book.Categories)
+	assert.Len(t, book.IndustryIdentifiers, 2)
+	assert.Equal(t, service.IndustryIdentifier{Type: "ISBN_13", Identifier: "9780134190570"}, book.IndustryIdentifiers[0])
+	assert.Equal(t, "https://books.google.com/books/content?id=zyTCAlFPjgYC&img=1", book.ThumbnailURL)
+
+	_, err = svc.GetVolume(t.Context(), "unknown")
+	assert.ErrorIs(t, err, service.ErrVolumeNotFound)
+
+	_, err = svc.GetVolume(t.Context(), "../volumes?q=x")
+	assert.ErrorIs(t, err, service.ErrValidation)
+}
+
+// TestGoogleBooks_SearchVolumes verifies that search results carry the same
+// details as single volumes.
+func TestGoogleBooks_SearchVolumes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"totalItems": 1, "items": [` + volumeJSON + `]}`))
+	}))
+	defer srv.Close()
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL})
+
+	res, err := svc.Search(t.Context(), service.SearchParams{Query: "go"})
+	assert.NoError(t, err)
+	assert.Len(t, res.Items, 1)
+	assert.Equal(t, "zyTCAlFPjgYC", res.Items[0].ID)
+	assert.Len(t, res.Items[0].Authors, 2)
+	assert.Len(t, res.Items[0].IndustryIdentifiers, 2)
+}
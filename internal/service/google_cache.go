@@ -0,0 +1,256 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+//
+// ─────────────────────────── GOOGLE BOOKS CACHE ───────────────────────────
+//
+
+// Default values applied by NewCachedGoogleBooks when the options leave them empty.
+const (
+	DefaultCacheSize = 1000
+	DefaultCacheTTL  = time.Hour
+)
+
+// CacheOptions configures the Google Books cache.
+type CacheOptions struct {
+	Size  int              // Maximum number of in-memory entries (default: DefaultCacheSize)
+	TTL   time.Duration    // How long a result stays fresh (default: DefaultCacheTTL)
+	Store CacheStore       // Optional persistent second level, e.g. the SQLite database
+	Now   func() time.Time // Clock used for expiry (default: time.Now)
+}
+
+// CacheStats holds the cache counters exposed for monitoring.
+type CacheStats struct {
+	Hits           uint64 // Served from memory
+	PersistentHits uint64 // Served from the persistent store
+	Misses         uint64 // Forwarded to Google Books
+	Coalesced      uint64 // Shared an identical in-flight request
+	Evictions      uint64 // Removed to respect Size
+	Entries        int    // Current in-memory entries
+}
+
+// CacheStatsProvider is implemented by GoogleBooksUsecase decorators that cache results.
+type CacheStatsProvider interface {
+	CacheStats() CacheStats
+}
+
+// CachedGoogleBooks decorates a GoogleBooksUsecase with an in-memory LRU
+// cache, an optional persistent cache and request coalescing, so that
+// concurrent identical lookups share a single upstream call.
+// Only successful results are cached; errors are always passed through.
+type CachedGoogleBooks struct {
+	next  GoogleBooksUsecase
+	ttl   time.Duration
+	store CacheStore
+	now   func() time.Time
+
+	mu    sync.Mutex
+	size  int
+	order *list.List               // Front is most recently used
+	items map[string]*list.Element // Key → element holding *cacheItem
+
+	group singleflight.Group
+
+	hits, persistentHits, misses, coalesced, evictions atomic.Uint64
+}
+
+// cacheItem is one in-memory cache entry.
+type cacheItem struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// NewCachedGoogleBooks wraps next with a cache configured by opts.
+func NewCachedGoogleBooks(next GoogleBooksUsecase, opts CacheOptions) *CachedGoogleBooks {
+	if opts.Size <= 0 {
+		opts.Size = DefaultCacheSize
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultCacheTTL
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &CachedGoogleBooks{
+		next:  next,
+		ttl:   opts.TTL,
+		store: opts.Store,
+		now:   opts.Now,
+		size:  opts.Size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Search returns a cached result page for identical parameters, or forwards
+// the search to the wrapped client. Invalid parameters are not cached.
+func (c *CachedGoogleBooks) Search(ctx context.Context, p SearchParams) (*SearchResult, error) {
+	key := p
+	if err := key.normalize(); err != nil {
+		return c.next.Search(ctx, p)
+	}
+	raw, _ := json.Marshal(key)
+	v, err := cached(ctx, c, "search:"+string(raw), func(ctx context.Context) (*SearchResult, error) { return c.next.Search(ctx, p) })
+	if err != nil {
+		return nil, err
+	}
+	res := *v
+	res.Items = append([]GoogleBook(nil), v.Items...)
+	return &res, nil
+}
+
+// GetVolume returns a cached volume or fetches it from the wrapped client.
+func (c *CachedGoogleBooks) GetVolume(ctx context.Context, volumeID string) (*GoogleBook, error) {
+	v, err := cached(ctx, c, "volume:"+volumeID, func(ctx context.Context) (*GoogleBook, error) { return c.next.GetVolume(ctx, volumeID) })
+	if err != nil {
+		return nil, err
+	}
+	book := *v
+	return &book, nil
+}
+
+// CacheStats returns a snapshot of the cache counters.
+func (c *CachedGoogleBooks) CacheStats() CacheStats {
+	c.mu.Lock()
+	entries := c.order.Len()
+	c.mu.Unlock()
+	return CacheStats{
+		Hits:           c.hits.Load(),
+		PersistentHits: c.persistentHits.Load(),
+		Misses:         c.misses.Load(),
+		Coalesced:      c.coalesced.Load(),
+		Evictions:      c.evictions.Load(),
+		Entries:        entries,
+	}
+}
+
+// cached looks key up in memory, then in the persistent store, and finally
+// calls fetch. Concurrent misses for the same key share one fetch, which runs
+// with the context of the caller that started it. Each caller returns as soon
+// as its own ctx is done; if the shared fetch failed only because the first
+// caller went away, the others start a new one.
+func cached[T any](ctx context.Context, c *CachedGoogleBooks, key string, fetch func(context.Context) (*T, error)) (*T, error) {
+	if v, ok := c.get(key); ok {
+		c.hits.Add(1)
+		return v.(*T), nil
+	}
+
+	for {
+		leader := false
+		ch := c.group.DoChan(key, func() (any, error) {
+			leader = true
+			// Another caller may have filled the cache while we waited for the lock.
+			if v, ok := c.get(key); ok {
+				c.hits.Add(1)
+				return v, nil
+			}
+			if v, ok := loadPersistent[T](ctx, c, key); ok {
+				c.persistentHits.Add(1)
+				c.set(key, v, c.now().Add(c.ttl))
+				return v, nil
+			}
+			c.misses.Add(1)
+			v, err := fetch(ctx)
+			if err != nil {
+				return nil, err
+			}
+			expiresAt := c.now().Add(c.ttl)
+			c.set(key, v, expiresAt)
+			savePersistent(context.WithoutCancel(ctx), c, key, v, expiresAt)
+			return v, nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case res := <-ch:
+			if !leader {
+				if isContextError(res.Err) && ctx.Err() == nil {
+					continue
+				}
+				c.coalesced.Add(1)
+			}
+			if res.Err != nil {
+				return nil, res.Err
+			}
+			return res.Val.(*T), nil
+		}
+	}
+}
+
+// get returns a fresh in-memory entry and marks it as recently used.
+// Expired entries are removed.
+func (c *CachedGoogleBooks) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := el.Value.(*cacheItem)
+	if !c.now().Before(item.expiresAt) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return item.value, true
+}
+
+// set stores an entry, evicting the least recently used one when full.
+func (c *CachedGoogleBooks) set(key string, value any, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		item := el.Value.(*cacheItem)
+		item.value, item.expiresAt = value, expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&cacheItem{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheItem).key)
+		c.evictions.Add(1)
+	}
+}
+
+// loadPersistent reads a fresh entry from the persistent store, if any.
+// Store errors and undecodable entries are treated as misses.
+func loadPersistent[T any](ctx context.Context, c *CachedGoogleBooks, key string) (*T, bool) {
+	if c.store == nil {
+		return nil, false
+	}
+	raw, expiresAt, err := c.store.Get(ctx, key)
+	if err != nil || !c.now().Before(expiresAt) {
+		return nil, false
+	}
+	v := new(T)
+	if err := json.Unmarshal(raw, v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// savePersistent writes an entry to the persistent store on a best-effort
+// basis; a failing store must not fail the lookup.
+func savePersistent(ctx context.Context, c *CachedGoogleBooks, key string, v any, expiresAt time.Time) {
+	if c.store == nil {
+		return
+	}
+	if raw, err := json.Marshal(v); err == nil {
+		c.store.Set(ctx, key, raw, expiresAt.UTC())
+	}
+}
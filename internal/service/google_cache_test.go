@@ -0,0 +1,273 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeGoogle counts upstream calls. When gate is set, searches block until it
+// is closed or their context is done.
+type fakeGoogle struct {
+	calls atomic.Int32
+	gate  chan struct{}
+	err   error
+}
+
+func (f *fakeGoogle) Search(ctx context.Context, p service.SearchParams) (*service.SearchResult, error) {
+	f.calls.Add(1)
+	if f.gate != nil {
+		select {
+		case <-f.gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &service.SearchResult{TotalItems: 1, Items: []service.GoogleBook{{ID: "vol1", Title: p.Query}}}, nil
+}
+
+func (f *fakeGoogle) GetVolume(ctx context.Context, volumeID string) (*service.GoogleBook, error) {
+	f.calls.Add(1)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &service.GoogleBook{ID: volumeID, Title: "Title of " + volumeID, ISBNs: []string{"9780134190440"}}, nil
+}
+
+// memoryStore is an in-memory CacheStore.
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+	expires map[string]time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{entries: map[string][]byte{}, expires: map[string]time.Time{}}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) ([]byte, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.entries[key]
+	if !ok {
+		return nil, time.Time{}, service.ErrNotFound
+	}
+	return v, s.expires[key], nil
+}
+
+func (s *memoryStore) Set(ctx context.Context, key string, value []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key], s.expires[key] = value, expiresAt
+	return nil
+}
+
+func (s *memoryStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) { return 0, nil }
+
+// TestCachedGoogleBooks_Hits verifies that identical lookups are served from
+// memory, that equivalent search parameters share an entry and that callers
+// cannot modify the cached value.
+func TestCachedGoogleBooks_Hits(t *testing.T) {
+	up := &fakeGoogle{}
+	c := service.NewCachedGoogleBooks(up, service.CacheOptions{})
+
+	res, err := c.Search(t.Context(), service.SearchParams{Query: "golang"})
+	assert.NoError(t, err)
+	res.Items[0].Title = "modified"
+
+	// Defaults applied explicitly produce the same cache key
+	res, err = c.Search(t.Context(), service.SearchParams{Query: " golang ", MaxResults: service.DefaultSearchMaxResults})
+	assert.NoError(t, err)
+	assert.Equal(t, "golang", res.Items[0].Title)
+
+	_, err = c.GetVolume(t.Context(), "vol1")
+	assert.NoError(t, err)
+	_, err = c.GetVolume(t.Context(), "vol1")
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 2, up.calls.Load())
+	assert.Equal(t, service.CacheStats{Hits: 2, Misses: 2, Entries: 2}, c.CacheStats())
+}
+
+// TestCachedGoogleBooks_Errors verifies that failures and invalid parameters are never cached.
+func TestCachedGoogleBooks_Errors(t *testing.T) {
+	up := &fakeGoogle{err: service.NewError(service.ErrUpstreamUnavailable, "google books is unreachable")}
+	c := service.NewCachedGoogleBooks(up, service.CacheOptions{})
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetVolume(t.Context(), "vol1")
+		assert.ErrorIs(t, err, service.ErrUpstreamUnavailable)
+	}
+	assert.EqualValues(t, 2, up.calls.Load())
+
+	up.err = nil
+	_, err := c.Search(t.Context(), service.SearchParams{})
+	assert.NoError(t, err, "invalid params are forwarded as-is")
+	assert.Equal(t, 0, c.CacheStats().Entries)
+}
+
+// TestCachedGoogleBooks_TTL verifies that entries expire after the TTL.
+func TestCachedGoogleBooks_TTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	up := &fakeGoogle{}
+	c := service.NewCachedGoogleBooks(up, service.CacheOptions{TTL: time.Minute, Now: func() time.Time { return now }})
+
+	c.GetVolume(t.Context(), "vol1")
+	now = now.Add(59 * time.Second)
+	c.GetVolume(t.Context(), "vol1")
+	assert.EqualValues(t, 1, up.calls.Load())
+
+	now = now.Add(time.Second)
+	c.GetVolume(t.Context(), "vol1")
+	assert.EqualValues(t, 2, up.calls.Load())
+}
+
+// TestCachedGoogleBooks_LRU verifies that the least recently used entry is evicted.
+func TestCachedGoogleBooks_LRU(t *testing.T) {
+	up := &fakeGoogle{}
+	c := service.NewCachedGoogleBooks(up, service.CacheOptions{Size: 2})
+
+	c.GetVolume(t.Context(), "a")
+	c.GetVolume(t.Context(), "b")
+	c.GetVolume(t.Context(), "a") // a is now the most recently used
+	c.GetVolume(t.Context(), "c") // evicts b
+	assert.EqualValues(t, 3, up.calls.Load())
+
+	c.GetVolume(t.Context(), "a")
+	assert.EqualValues(t, 3, up.calls.Load())
+	c.GetVolume(t.Context(), "b")
+	assert.EqualValues(t, 4, up.calls.Load())
+
+	stats := c.CacheStats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.EqualValues(t, 2, stats.Evictions)
+}
+
+// TestCachedGoogleBooks_Coalescing verifies that concurrent identical
+// searches share a single upstream call.
+func TestCachedGoogleBooks_Coalescing(t *testing.T) {
+	up := &fakeGoogle{gate: make(chan struct{})}
+	c := service.NewCachedGoogleBooks(up, service.CacheOptions{})
+
+	const callers = 5
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := c.Search(t.Context(), service.SearchParams{Query: "golang"})
+			assert.NoError(t, err)
+			assert.Len(t, res.Items, 1)
+		}()
+	}
+
+	// Wait until the first call reached upstream and the others are queued behind it
+	assert.Eventually(t, func() bool { return up.calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(up.gate)
+	wg.Wait()
+
+	stats := c.CacheStats()
+	assert.EqualValues(t, 1, up.calls.Load())
+	assert.EqualValues(t, 1, stats.Misses)
+	assert.EqualValues(t, callers-1, stats.Hits+stats.Coalesced)
+}
+
+// TestCachedGoogleBooks_CoalescingCancel verifies that a caller whose context
+// is cancelled stops waiting, and that the callers it was fetching for start
+// a new upstream call instead of failing with its cancellation.
+func TestCachedGoogleBooks_CoalescingCancel(t *testing.T) {
+	up := &fakeGoogle{gate: make(chan struct{})}
+	c := service.NewCachedGoogleBooks(up, service.CacheOptions{})
+	params := service.SearchParams{Query: "golang"}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.Search(ctx, params)
+		leaderErr <- err
+	}()
+	assert.Eventually(t, func() bool { return up.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	followerErr := make(chan error, 1)
+	go func() {
+		_, err := c.Search(t.Context(), params)
+		followerErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-leaderErr, context.Canceled)
+
+	assert.Eventually(t, func() bool { return up.calls.Load() == 2 }, time.Second, time.Millisecond)
+	close(up.gate)
+	assert.NoError(t, <-followerErr)
+	assert.EqualValues(t, 1, c.CacheStats().Entries)
+}
+
+// TestCachedGoogleBooks_Persistent verifies that a new cache instance reuses
+// entries saved by a previous one, including the merged ISBNs, and ignores
+// expired or broken entries.
+func TestCachedGoogleBooks_Persistent(t *testing.T) {
+	store := newMemoryStore()
+	up := &fakeGoogle{}
+
+	first := service.NewCachedGoogleBooks(up, service.CacheOptions{Store: store})
+	_, err := first.GetVolume(t.Context(), "vol1")
+	assert.NoError(t, err)
+	assert.Len(t, store.entries, 1)
+
+	second := service.NewCachedGoogleBooks(up, service.CacheOptions{Store: store})
+	book, err := second.GetVolume(t.Context(), "vol1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Title of vol1", book.Title)
+	assert.Equal(t, []string{"9780134190440"}, book.ISBNs)
+	assert.EqualValues(t, 1, up.calls.Load())
+	assert.EqualValues(t, 1, second.CacheStats().PersistentHits)
+
+	// Expired entries are fetched again
+	later := service.NewCachedGoogleBooks(up, service.CacheOptions{
+		Store: store,
+		Now:   func() time.Time { return time.Now().Add(2 * service.DefaultCacheTTL) },
+	})
+	_, err = later.GetVolume(t.Context(), "vol1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, up.calls.Load())
+
+	// Broken entries are treated as misses
+	store.Set(t.Context(), "volume:vol2", []byte("{"), time.Now().Add(time.Hour))
+	book, err = second.GetVolume(t.Context(), "vol2")
+	assert.NoError(t, err)
+	assert.Equal(t, "vol2", book.ID)
+	assert.EqualValues(t, 3, up.calls.Load())
+}
+
+// TestCachedGoogleBooks_StoreErrors verifies that a failing store does not fail lookups.
+func TestCachedGoogleBooks_StoreErrors(t *testing.T) {
+	up := &fakeGoogle{}
+	c := service.NewCachedGoogleBooks(up, service.CacheOptions{Store: failingStore{}})
+	book, err := c.GetVolume(t.Context(), "vol1")
+	assert.NoError(t, err)
+	assert.Equal(t, "vol1", book.ID)
+}
+
+// failingStore is a CacheStore whose every operation fails.
+type failingStore struct{}
+
+func (failingStore) Get(context.Context, string) ([]byte, time.Time, error) {
+	return nil, time.Time{}, errors.New("disk I/O error")
+}
+func (failingStore) Set(context.Context, string, []byte, time.Time) error {
+	return errors.New("disk I/O error")
+}
+func (failingStore) DeleteExpired(context.Context, time.Time) (int64, error) {
+	return 0, errors.New("disk I/O error")
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//
+// ─────────────────────────── RETRIES ───────────────────────────
+//
+
+// Default retry and circuit breaker settings applied by NewGoogleBooksService.
+const (
+	DefaultGoogleBooksMaxRetries       = 2
+	DefaultGoogleBooksRetryBaseDelay   = 200 * time.Millisecond
+	DefaultGoogleBooksRetryMaxDelay    = 5 * time.Second
+	DefaultGoogleBooksBreakerThreshold = 5
+	DefaultGoogleBooksBreakerCooldown  = 30 * time.Second
+)
+
+// retryableStatus reports whether a failed response is worth retrying:
+// rate limits (429) and server errors. Other client errors, including
+// quota errors reported as 403, would fail again.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// backoff returns the wait before retry number attempt (0-based): the base
+// delay doubled per attempt, capped at max, with up to half of it randomized
+// so that clients failing together do not retry together.
+func backoff(attempt int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 0; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	d = min(d, max)
+	half := d / 2
+	return d - half + rand.N(half+1)
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date. Returns 0 when the header is absent or invalid.
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first,
+// returning ctx's error in the latter case.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+//
+// ─────────────────────────── CIRCUIT BREAKER ───────────────────────────
+//
+
+// ErrCircuitOpen is returned without contacting Google Books while the
+// circuit breaker is open.
+var ErrCircuitOpen = NewError(ErrUpstreamUnavailable, "google books is temporarily unavailable, try again later")
+
+// circuitBreaker stops calls to an upstream that keeps failing.
+//
+// After threshold consecutive failed requests the circuit opens and every call
+// fails fast with ErrCircuitOpen. Once cooldown has elapsed a single trial
+// request is let through: success closes the circuit, failure reopens it.
+//
+// Every state change starts a new generation. allow hands each request the
+// generation it was admitted in, and only outcomes of the current generation
+// are recorded, so a request admitted before the circuit opened cannot close
+// it or reopen it while the trial request is in flight.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu         sync.Mutex
+	failures   int       // Consecutive failed requests
+	openUntil  time.Time // End of the current open period
+	probing    bool      // A trial request is in flight
+	generation uint64    // Incremented on every state change
+}
+
+// allow reports whether a request may be sent, returning ErrCircuitOpen if not.
+// The returned generation must be passed to record or release.
+// A nil breaker always allows requests.
+func (b *circuitBreaker) allow() (uint64, error) {
+	if b == nil {
+		return 0, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return b.generation, nil
+	}
+	if b.probing || b.now().Before(b.openUntil) {
+		return 0, ErrCircuitOpen
+	}
+	b.probing = true
+	b.generation++
+	return b.generation, nil
+}
+
+// release gives up a request allowed by allow without recording an outcome,
+// e.g. because the caller went away before Google answered. A released trial
+// request lets the next caller try instead.
+func (b *circuitBreaker) release(generation uint64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation == b.generation {
+		b.probing = false
+	}
+}
+
+// record updates the breaker with the outcome of a request allowed by allow.
+// Outcomes of requests admitted in an earlier generation are ignored.
+func (b *circuitBreaker) record(generation uint64, failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation != b.generation {
+		return
+	}
+	wasOpen := b.failures >= b.threshold
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		if wasOpen {
+			b.generation++
+		}
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+		b.generation++
+	}
+}
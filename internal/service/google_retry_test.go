@@ -0,0 +1,239 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyServer answers with the given statuses in order, then with 200 and an
+// empty result. Extra headers are sent with every failed response.
+func flakyServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if n <= len(statuses) {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.Write([]byte(`{"totalItems":0}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+// TestGoogleBooks_Retry verifies that transient failures are retried with
+// exponential backoff and that permanent failures are not.
+func TestGoogleBooks_Retry(t *testing.T) {
+	srv, calls := flakyServer(t, nil, http.StatusServiceUnavailable, http.StatusBadGateway)
+	var waits []time.Duration
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{
+		BaseURL:        srv.URL,
+		RetryBaseDelay: 100 * time.Millisecond,
+		Sleep:          func(_ context.Context, d time.Duration) error { waits = append(waits, d); return nil },
+	})
+
+	_, err := svc.Search(t.Context(), service.SearchParams{Query: "golang"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, calls.Load())
+	if assert.Len(t, waits, 2) {
+		assert.InDelta(t, 75*time.Millisecond, waits[0], float64(25*time.Millisecond))
+		assert.InDelta(t, 150*time.Millisecond, waits[1], float64(50*time.Millisecond))
+	}
+
+	// Retries are bounded
+	srv, calls = flakyServer(t, nil, 500, 500, 500, 500)
+	svc = service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL, MaxRetries: 1, Sleep: func(context.Context, time.Duration) error { return nil }})
+	_, err = svc.GetVolume(t.Context(), "vol1")
+	assert.ErrorIs(t, err, service.ErrUpstreamUnavailable)
+	assert.EqualValues(t, 2, calls.Load())
+
+	// Rejected requests and quota errors reported as 403 are not retried
+	for _, status := range []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound} {
+		srv, calls = flakyServer(t, nil, status)
+		svc = service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL, Sleep: func(context.Context, time.Duration) error { return nil }})
+		_, err = svc.GetVolume(t.Context(), "vol1")
+		assert.Error(t, err)
+		assert.EqualValues(t, 1, calls.Load(), "status %d", status)
+	}
+}
+
+// TestGoogleBooks_RetryAfter verifies that Retry-After is honored on 429
+// and that waits longer than RetryMaxDelay are not attempted.
+func TestGoogleBooks_RetryAfter(t *testing.T) {
+	srv, calls := flakyServer(t, http.Header{"Retry-After": {"3"}}, http.StatusTooManyRequests)
+	var waits []time.Duration
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{
+		BaseURL: srv.URL,
+		Sleep:   func(_ context.Context, d time.Duration) error { waits = append(waits, d); return nil },
+	})
+	_, err := svc.Search(t.Context(), service.SearchParams{Query: "golang"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, calls.Load())
+	assert.Equal(t, []time.Duration{3 * time.Second}, waits)
+
+	srv, calls = flakyServer(t, http.Header{"Retry-After": {"3600"}}, http.StatusTooManyRequests)
+	waits = nil
+	svc = service.NewGoogleBooksService(service.GoogleBooksOptions{
+		BaseURL: srv.URL,
+		Sleep:   func(_ context.Context, d time.Duration) error { waits = append(waits, d); return nil },
+	})
+	_, err = svc.Search(t.Context(), service.SearchParams{Query: "golang"})
+	assert.ErrorIs(t, err, service.ErrUpstreamQuota)
+	assert.EqualValues(t, 1, calls.Load())
+	assert.Empty(t, waits)
+}
+
+// TestGoogleBooks_CircuitBreaker verifies that the client stops calling a
+// failing upstream, fails fast with a 503-class error, and recovers after
+// the cooldown once a trial request succeeds.
+func TestGoogleBooks_CircuitBreaker(t *testing.T) {
+	srv, calls := flakyServer(t, nil, 500, 500, 500)
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{
+		BaseURL:          srv.URL,
+		MaxRetries:       -1,
+		BreakerThreshold: 2,
+		BreakerCooldown:  50 * time.Millisecond,
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := svc.GetVolume(t.Context(), "vol1")
+		assert.ErrorIs(t, err, service.ErrUpstreamUnavailable)
+	}
+	_, err := svc.GetVolume(t.Context(), "vol1")
+	assert.ErrorIs(t, err, service.ErrCircuitOpen)
+	assert.ErrorIs(t, err, service.ErrUpstreamUnavailable)
+	assert.EqualValues(t, 2, calls.Load(), "open circuit must not reach upstream")
+
+	// The trial request after the cooldown fails and reopens the circuit
+	time.Sleep(60 * time.Millisecond)
+	_, err = svc.GetVolume(t.Context(), "vol1")
+	assert.NotErrorIs(t, err, service.ErrCircuitOpen)
+	_, err = svc.GetVolume(t.Context(), "vol1")
+	assert.ErrorIs(t, err, service.ErrCircuitOpen)
+	assert.EqualValues(t, 3, calls.Load())
+
+	// The next trial succeeds and closes the circuit
+	time.Sleep(60 * time.Millisecond)
+	_, err = svc.Search(t.Context(), service.SearchParams{Query: "golang"})
+	assert.NoError(t, err)
+	_, err = svc.Search(t.Context(), service.SearchParams{Query: "golang"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, calls.Load())
+
+	// Client errors do not count as upstream failures
+	srv, calls = flakyServer(t, nil, 400, 400, 400)
+	svc = service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL, BreakerThreshold: 2})
+	for i := 0; i < 3; i++ {
+		_, err = svc.GetVolume(t.Context(), "vol1")
+		assert.ErrorIs(t, err, service.ErrUpstreamBadRequest)
+	}
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+// TestGoogleBooks_CircuitBreakerStaleRequest verifies that a request admitted
+// before the circuit opened cannot close it while the trial request is in flight.
+func TestGoogleBooks_CircuitBreakerStaleRequest(t *testing.T) {
+	staleGate, probeGate := make(chan struct{}), make(chan struct{})
+	arrived := make(chan string, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := path.Base(r.URL.Path)
+		arrived <- id
+		switch id {
+		case "stale":
+			<-staleGate
+		case "probe":
+			<-probeGate
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"id":"` + id + `"}`))
+	}))
+	defer srv.Close()
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{
+		BaseURL:          srv.URL,
+		MaxRetries:       -1,
+		BreakerThreshold: 1,
+		BreakerCooldown:  20 * time.Millisecond,
+	})
+	get := func(id string) <-chan error {
+		done := make(chan error, 1)
+		go func() {
+			_, err := svc.GetVolume(t.Context(), id)
+			done <- err
+		}()
+		assert.Equal(t, id, <-arrived)
+		return done
+	}
+
+	stale := get("stale")
+	assert.ErrorIs(t, <-get("fail"), service.ErrUpstreamUnavailable)
+	time.Sleep(30 * time.Millisecond)
+	probe := get("probe")
+
+	// The stale request succeeds during the trial: the circuit stays half-open
+	close(staleGate)
+	assert.NoError(t, <-stale)
+	_, err := svc.GetVolume(t.Context(), "fail")
+	assert.ErrorIs(t, err, service.ErrCircuitOpen)
+
+	// The trial request closes the circuit
+	close(probeGate)
+	assert.NoError(t, <-probe)
+	assert.NotErrorIs(t, <-get("fail"), service.ErrCircuitOpen)
+}
+
+// TestGoogleBooks_Context verifies that a cancelled or expired context stops
+// the request and its retries, is reported as the context's error, and does
+// not count towards the circuit breaker.
+func TestGoogleBooks_Context(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	defer close(release)
+	svc := service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv.URL, BreakerThreshold: 1})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	_, err := svc.GetVolume(ctx, "vol1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, service.ErrUpstreamUnavailable)
+	assert.EqualValues(t, 1, calls.Load(), "cancelled requests are not retried")
+
+	// With a threshold of 1 a counted failure would have opened the circuit
+	ctx, cancel = context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	_, err = svc.GetVolume(ctx, "vol1")
+	assert.NotErrorIs(t, err, service.ErrCircuitOpen)
+	assert.EqualValues(t, 2, calls.Load())
+
+	// Waiting between retries stops as soon as the context is done
+	srv2, calls2 := flakyServer(t, nil, 500, 500)
+	svc = service.NewGoogleBooksService(service.GoogleBooksOptions{BaseURL: srv2.URL, RetryBaseDelay: time.Hour, RetryMaxDelay: time.Hour})
+	ctx, cancel = context.WithCancel(t.Context())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = svc.GetVolume(ctx, "vol1")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.EqualValues(t, 1, calls2.Load())
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"strings"
+)
+
+//
+// ─────────────────────────── SEARCH PARAMETERS ───────────────────────────
+//
+
+// Pagination limits enforced by the Google Books API.
+const (
+	DefaultSearchMaxResults = 10
+	MaxSearchMaxResults     = 40
+)
+
+// SearchParams describes a Google Books search.
+// At least one of Query, Title, Author, ISBN or Subject is required; the
+// field-qualified ones are sent as intitle:, inauthor:, isbn: and subject:.
+type SearchParams struct {
+	Query      string // Free-text terms
+	Title      string // Words in the title (intitle:)
+	Author     string // Words in the author name (inauthor:)
+	ISBN       string // ISBN-10 or ISBN-13, hyphens allowed (isbn:)
+	Subject    string // Category (subject:)
+	StartIndex int    // Zero-based index of the first result
+	MaxResults int    // Page size, 1-40 (default: DefaultSearchMaxResults)
+	OrderBy    string // "relevance" (default) or "newest"
+	PrintType  string // "all" (default), "books" or "magazines"
+	Language   string // Two-letter ISO 639-1 code to restrict results to
+}
+
+// SearchResult is one page of Google Books search results.
+type SearchResult struct {
+	TotalItems     int          // Total matches reported by Google (approximate)
+	StartIndex     int          // Index of the first item in Items
+	MaxResults     int          // Page size used for the request
+	Items          []GoogleBook // Results on this page
+	HasMore        bool         // Whether another page is likely available
+	NextStartIndex int          // StartIndex of the next page (valid when HasMore)
+
+	Provider       string            // Provider that answered, or ProviderAggregate (set by CatalogUsecase)
+	ProviderErrors map[string]string // Aggregate searches only: providers that failed and why
+}
+
+// normalize trims the parameters, applies defaults and validates them.
+// Returns an ErrValidation error describing the first invalid parameter.
+func (p *SearchParams) normalize() error {
+	for _, f := range []*string{&p.Query, &p.Title, &p.Author, &p.Subject, &p.OrderBy, &p.PrintType, &p.Language} {
+		*f = strings.TrimSpace(*f)
+	}
+	p.ISBN = NormalizeISBN(p.ISBN)
+	p.OrderBy = strings.ToLower(p.OrderBy)
+	p.PrintType = strings.ToLower(p.PrintType)
+	p.Language = strings.ToLower(p.Language)
+
+	if p.Query == "" && p.Title == "" && p.Author == "" && p.ISBN == "" && p.Subject == "" {
+		return validationErrorf("search requires at least one of q, title, author, isbn or subject")
+	}
+	if p.StartIndex < 0 {
+		return validationErrorf("startIndex cannot be negative")
+	}
+	if p.MaxResults == 0 {
+		p.MaxResults = DefaultSearchMaxResults
+	}
+	if p.MaxResults < 1 || p.MaxResults > MaxSearchMaxResults {
+		return validationErrorf("maxResults must be between 1 and %d", MaxSearchMaxResults)
+	}
+	switch p.OrderBy {
+	case "", "relevance", "newest":
+	default:
+		return validationErrorf("orderBy must be relevance or newest")
+	}
+	switch p.PrintType {
+	case "", "all", "books", "magazines":
+	default:
+		return validationErrorf("printType must be all, books or magazines")
+	}
+	if p.Language != "" && (len(p.Language) != 2 || !isLower(p.Language)) {
+		return validationErrorf("language must be a two-letter ISO 639-1 code")
+	}
+	return nil
+}
+
+// googleQuery builds Google's q parameter from the free text and the
+// field-qualified terms. Multi-word values are quoted so the qualifier
+// applies to the whole phrase.
+func (p *SearchParams) googleQuery() string {
+	terms := []string{}
+	if p.Query != "" {
+		terms = append(terms, p.Query)
+	}
+	for _, f := range []struct{ prefix, value string }{
+		{"intitle:", p.Title},
+		{"inauthor:", p.Author},
+		{"isbn:", p.ISBN},
+		{"subject:", p.Subject},
+	} {
+		if f.value == "" {
+			continue
+		}
+		v := strings.ReplaceAll(f.value, `"`, "")
+		if strings.ContainsAny(v, " \t") {
+			v = `"` + v + `"`
+		}
+		terms = append(terms, f.prefix+v)
+	}
+	return strings.Join(terms, " ")
+}
+
+// isLower reports whether s consists only of ASCII lower-case letters.
+func isLower(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
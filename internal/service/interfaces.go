@@ -1,47 +1,133 @@
 package service
 
+import (
+	"context"
+	"time"
+)
+
 //
 // ─────────────────────────── USE CASE INTERFACES ───────────────────────────
 //
 
+// Every use case and repository method takes the caller's context first.
+// Implementations hand it to the database and to upstream HTTP calls, so a
+// cancelled request or an expired deadline stops the work and the method
+// returns the context's error.
+
 // UserUsecase defines the business logic for users.
 type UserUsecase interface {
 	// Register creates a new user with username and password.
-	Register(username, password string) error
+	Register(ctx context.Context, username, password string) error
+
+	// List retrieves one page of registered users.
+	List(ctx context.Context, opts ListOptions) (*Page[User], error)
+
+	// Get retrieves a single user by ID.
+	Get(ctx context.Context, userID uint) (*User, error)
 
-	// List retrieves all registered users.
-	List() ([]User, error)
+	// Authenticate verifies the credentials and returns the matching user.
+	Authenticate(ctx context.Context, username, password string) (*User, error)
 }
 
 // WishlistUsecase defines the business logic for wishlists.
 type WishlistUsecase interface {
-	// Create adds a new wishlist for a given user.
-	Create(userID uint, name string) error
+	// Create adds a new wishlist for a given user and returns it with its ID.
+	Create(ctx context.Context, userID uint, name string) (*Wishlist, error)
 
-	// List retrieves all wishlists for a given user.
-	List(userID uint) ([]Wishlist, error)
+	// List retrieves one page of the wishlists of a given user.
+	List(ctx context.Context, userID uint, opts ListOptions) (*Page[Wishlist], error)
 
-	// Delete removes a wishlist by its ID for a given user.
-	Delete(userID, wishlistID uint) error
+	// Get retrieves a single wishlist owned by the user.
+	Get(ctx context.Context, userID, wishlistID uint) (*Wishlist, error)
+
+	// Update applies the non-nil fields of upd to a wishlist owned by the user.
+	Update(ctx context.Context, userID, wishlistID uint, upd WishlistUpdate) (*Wishlist, error)
+
+	// Delete moves a wishlist, with its books, to the user's trash.
+	Delete(ctx context.Context, userID, wishlistID uint) error
 }
 
 // BookUsecase defines the business logic for books inside wishlists.
+// Every method verifies that the wishlist belongs to the given user.
 type BookUsecase interface {
-	// Add inserts a new book into a wishlist.
-	Add(wishlistID uint, title, author string) error
+	// Add inserts a new book into a wishlist owned by the user and returns it with its ID.
+	// policy controls what happens when the wishlist already holds the same book;
+	// created is false when the book was merged into an existing entry.
+	Add(ctx context.Context, userID, wishlistID uint, book Book, policy DuplicatePolicy) (b *Book, created bool, err error)
 
-	// List retrieves all books in a given wishlist.
-	List(wishlistID uint) ([]Book, error)
+	// AddFromGoogle imports a Google Books volume into a wishlist owned by the user.
+	// Duplicates are handled as in Add.
+	AddFromGoogle(ctx context.Context, userID, wishlistID uint, volumeID string, policy DuplicatePolicy) (b *Book, created bool, err error)
+
+	// List retrieves one page of the books in a wishlist owned by the user.
+	List(ctx context.Context, userID, wishlistID uint, opts ListOptions) (*Page[Book], error)
+
+	// Get retrieves a single book from a wishlist owned by the user.
+	Get(ctx context.Context, userID, wishlistID, bookID uint) (*Book, error)
+
+	// Update applies the non-nil fields of upd to a book in a wishlist owned by the user.
+	Update(ctx context.Context, userID, wishlistID, bookID uint, upd BookUpdate) (*Book, error)
+
+	// Delete moves a book from a wishlist owned by the user to the user's trash.
+	Delete(ctx context.Context, userID, wishlistID, bookID uint) error
+
+	// Duplicates lists groups of likely duplicate books in a wishlist owned by the user.
+	Duplicates(ctx context.Context, userID, wishlistID uint) ([]DuplicateGroup, error)
+}
 
-	// Delete removes a book by its ID from a wishlist.
-	Delete(wishlistID, bookID uint) error
+// WishlistSearchUsecase defines full-text search across a user's own wishlists.
+type WishlistSearchUsecase interface {
+	// Search returns up to limit of the user's books matching query, best match first.
+	Search(ctx context.Context, userID uint, query string, limit int) ([]BookHit, error)
+}
+
+// TrashUsecase defines access to a user's soft-deleted wishlists and books.
+type TrashUsecase interface {
+	// List retrieves the user's trash, most recently deleted first.
+	List(ctx context.Context, userID uint) (*Trash, error)
+
+	// RestoreWishlist takes a wishlist out of the user's trash, with its books.
+	RestoreWishlist(ctx context.Context, userID, wishlistID uint) (*Wishlist, error)
+
+	// RestoreBook takes a book out of the user's trash, back into its wishlist.
+	RestoreBook(ctx context.Context, userID, wishlistID, bookID uint) (*Book, error)
+}
+
+// EnrichmentUsecase defines on-demand metadata enrichment of wishlist books.
+type EnrichmentUsecase interface {
+	// Enrich starts a background job that looks up the books of a wishlist owned
+	// by the user that lack metadata. If a job for the wishlist is still running,
+	// that job is returned instead.
+	Enrich(ctx context.Context, userID, wishlistID uint) (*EnrichJob, error)
+
+	// Job returns the current state of an enrichment job started by the user.
+	Job(ctx context.Context, userID uint, jobID string) (*EnrichJob, error)
 }
 
 // GoogleBooksUsecase defines the contract for searching books via Google Books API.
 type GoogleBooksUsecase interface {
 	// Search performs a query against the Google Books API
-	// and returns a simplified list of results.
-	Search(query string) ([]GoogleBook, error)
+	// and returns one page of simplified results.
+	Search(ctx context.Context, params SearchParams) (*SearchResult, error)
+
+	// GetVolume fetches a single volume by its Google Books ID.
+	GetVolume(ctx context.Context, volumeID string) (*GoogleBook, error)
+}
+
+// BookSearcher is implemented by every catalogue provider (Google Books, Open Library).
+type BookSearcher interface {
+	// Search returns one page of results in the common GoogleBook shape.
+	Search(ctx context.Context, params SearchParams) (*SearchResult, error)
+}
+
+// CatalogUsecase searches the configured book metadata providers.
+type CatalogUsecase interface {
+	// Search runs params against the named provider. An empty name selects the
+	// default provider; ProviderAggregate queries all of them and merges the results.
+	Search(ctx context.Context, provider string, params SearchParams) (*SearchResult, error)
+
+	// Providers lists the configured provider names in order of precedence.
+	Providers() []string
 }
 
 //
@@ -51,32 +137,113 @@ type GoogleBooksUsecase interface {
 // UserRepository defines persistence operations for users.
 type UserRepository interface {
 	// Add saves a new user to the database.
-	Add(u *User) error
+	Add(ctx context.Context, u *User) error
 
 	// List retrieves all users from the database.
-	List() ([]User, error)
+	List(ctx context.Context) ([]User, error)
+
+	// ListPage retrieves one page of users. opts has already been normalized.
+	ListPage(ctx context.Context, opts ListOptions) (*Page[User], error)
+
+	// GetByID retrieves a single user by ID.
+	GetByID(ctx context.Context, userID uint) (*User, error)
+
+	// GetByUsername retrieves a single user by username.
+	GetByUsername(ctx context.Context, username string) (*User, error)
+
+	// UpdatePassword replaces the stored password hash of a user.
+	UpdatePassword(ctx context.Context, userID uint, hash string) error
+
+	// SetAdmin grants or revokes admin privileges for a username.
+	SetAdmin(ctx context.Context, username string, admin bool) error
 }
 
 // WishlistRepository defines persistence operations for wishlists.
 type WishlistRepository interface {
 	// Add saves a new wishlist to the database.
-	Add(w *Wishlist) error
+	Add(ctx context.Context, w *Wishlist) error
 
-	// List retrieves all wishlists for a given user.
-	List(userID uint) ([]Wishlist, error)
+	// ListPage retrieves one page of the wishlists of a given user.
+	// opts has already been normalized.
+	ListPage(ctx context.Context, userID uint, opts ListOptions) (*Page[Wishlist], error)
 
-	// Delete removes a wishlist by its ID for a given user.
-	Delete(userID, wishlistID uint) error
+	// Get retrieves a single wishlist by its ID, regardless of owner.
+	Get(ctx context.Context, wishlistID uint) (*Wishlist, error)
+
+	// Update persists the editable fields of an existing wishlist.
+	Update(ctx context.Context, w *Wishlist) error
+
+	// Delete soft-deletes a wishlist by its ID for a given user.
+	Delete(ctx context.Context, userID, wishlistID uint) error
+
+	// ListDeleted retrieves the soft-deleted wishlists of a given user,
+	// most recently deleted first.
+	ListDeleted(ctx context.Context, userID uint) ([]Wishlist, error)
+
+	// Restore clears the deletion of a soft-deleted wishlist of a given user.
+	Restore(ctx context.Context, userID, wishlistID uint) error
+
+	// Purge permanently deletes the wishlists, and their books, soft-deleted
+	// before the given time, and returns how many wishlists were removed.
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
 
 // BookRepository defines persistence operations for books.
 type BookRepository interface {
 	// Add saves a new book to the database.
-	Add(b *Book) error
+	Add(ctx context.Context, b *Book) error
 
 	// List retrieves all books in a given wishlist.
-	List(wishlistID uint) ([]Book, error)
+	List(ctx context.Context, wishlistID uint) ([]Book, error)
+
+	// ListPage retrieves one page of the books in a given wishlist.
+	// opts has already been normalized.
+	ListPage(ctx context.Context, wishlistID uint, opts ListOptions) (*Page[Book], error)
+
+	// Get retrieves a single book by its ID from a wishlist.
+	Get(ctx context.Context, wishlistID, bookID uint) (*Book, error)
+
+	// Update persists the editable fields of an existing book.
+	Update(ctx context.Context, b *Book) error
+
+	// UpdateFields persists only the given fields of an existing book, named
+	// as in the Book struct, leaving concurrent changes to other fields intact.
+	UpdateFields(ctx context.Context, b *Book, fields ...string) error
+
+	// Delete soft-deletes a book by its ID from a wishlist.
+	Delete(ctx context.Context, wishlistID, bookID uint) error
+
+	// ListDeleted retrieves the soft-deleted books in the live wishlists of a
+	// given user, most recently deleted first.
+	ListDeleted(ctx context.Context, userID uint) ([]Book, error)
+
+	// Restore clears the deletion of a soft-deleted book in a wishlist.
+	Restore(ctx context.Context, wishlistID, bookID uint) error
+
+	// Purge permanently deletes the books soft-deleted before the given time
+	// and returns how many were removed.
+	Purge(ctx context.Context, before time.Time) (int64, error)
+
+	// Search retrieves up to limit books, across the wishlists of a user, whose
+	// title, authors, description or notes contain every term as a word or a
+	// word prefix. Terms are lower-cased words of letters and digits.
+	// Hits are ranked best first and carry their wishlist and highlights.
+	Search(ctx context.Context, userID uint, terms []string, limit int) ([]BookHit, error)
+
+	// ListMissingMetadata retrieves up to limit books, across all wishlists, that
+	// lack an ISBN or a cover and have never been through metadata enrichment.
+	ListMissingMetadata(ctx context.Context, limit int) ([]Book, error)
+}
+
+// CacheStore persists cached Google Books responses so they survive restarts.
+type CacheStore interface {
+	// Get returns the value stored under key and when it expires.
+	// Returns ErrNotFound if the key is not stored.
+	Get(ctx context.Context, key string) (value []byte, expiresAt time.Time, err error)
+
+	// Set stores value under key, replacing any previous entry.
+	Set(ctx context.Context, key string, value []byte, expiresAt time.Time) error
 
-	// Delete removes a book by its ID from a wishlist.
-	Delete(wishlistID, bookID uint) error
+	// DeleteExpired removes the entries that expired before now and returns how many were removed.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
 }
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+//
+// ─────────────────────────── ISBN ───────────────────────────
+//
+
+// ISBNCheck describes the result of validating a user-supplied ISBN.
+type ISBNCheck struct {
+	Input  string // Value as received
+	Valid  bool   // Whether the value is a well-formed ISBN with a correct check digit
+	ISBN10 string // Normalized ISBN-10 (empty for 979-prefixed ISBN-13s or invalid input)
+	ISBN13 string // Normalized, canonical ISBN-13 (empty for invalid input)
+	Reason string // Why the value is invalid (empty when Valid)
+}
+
+// CheckISBN validates an ISBN-10 or ISBN-13 and returns both normalized forms.
+// Invalid input is reported through Valid and Reason rather than an error,
+// so that clients can pre-check values before submitting them.
+func CheckISBN(input string) ISBNCheck {
+	check := ISBNCheck{Input: input}
+	isbn10, isbn13, err := ParseISBN(input)
+	if err != nil {
+		check.Reason = err.Error()
+		return check
+	}
+	check.Valid, check.ISBN10, check.ISBN13 = true, isbn10, isbn13
+	return check
+}
+
+// ParseISBN normalizes an ISBN-10 or ISBN-13 and returns both forms.
+// isbn10 is empty for ISBN-13s in the 979 range, which have no ISBN-10.
+// Returns an ErrValidation error if the value is malformed or its check digit is wrong.
+func ParseISBN(input string) (isbn10, isbn13 string, err error) {
+	s := NormalizeISBN(input)
+	switch len(s) {
+	case 10:
+		if !ValidISBN10(s) {
+			return "", "", validationErrorf("invalid ISBN-10 %q", input)
+		}
+		return s, ISBN10To13(s), nil
+	case 13:
+		if !ValidISBN13(s) {
+			return "", "", validationErrorf("invalid ISBN-13 %q", input)
+		}
+		isbn10, _ = ISBN13To10(s)
+		return isbn10, s, nil
+	default:
+		return "", "", validationErrorf("ISBN %q must have 10 or 13 digits", input)
+	}
+}
+
+// NormalizeISBN removes hyphens and whitespace and upper-cases the ISBN-10
+// check character "x". It does not validate the result.
+func NormalizeISBN(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '-' || unicode.IsSpace(r):
+			return -1
+		case r == 'x':
+			return 'X'
+		}
+		return r
+	}, s)
+}
+
+// ValidISBN10 reports whether s is a normalized ISBN-10 with a correct check digit.
+// The last character may be "X", representing 10.
+func ValidISBN10(s string) bool {
+	if len(s) != 10 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var d int
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			d = int(c - '0')
+		case c == 'X' && i == 9:
+			d = 10
+		default:
+			return false
+		}
+		sum += d * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+// ValidISBN13 reports whether s is a normalized ISBN-13 with a correct check digit.
+func ValidISBN13(s string) bool {
+	if len(s) != 13 || !allDigits(s) || !(strings.HasPrefix(s, "978") || strings.HasPrefix(s, "979")) {
+		return false
+	}
+	return isbn13CheckDigit(s[:12]) == s[12]
+}
+
+// ISBN10To13 converts a valid, normalized ISBN-10 to its canonical ISBN-13.
+func ISBN10To13(isbn10 string) string {
+	body := "978" + isbn10[:9]
+	return body + string(isbn13CheckDigit(body))
+}
+
+// ISBN13To10 converts a valid, normalized ISBN-13 to ISBN-10.
+// Returns false for 979-prefixed numbers, which have no ISBN-10 equivalent.
+func ISBN13To10(isbn13 string) (string, bool) {
+	if !strings.HasPrefix(isbn13, "978") {
+		return "", false
+	}
+	body := isbn13[3:12]
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return body + "X", true
+	}
+	return body + string(rune('0'+check)), true
+}
+
+// isbn13CheckDigit computes the EAN-13 check digit for the first 12 digits.
+func isbn13CheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(body[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// allDigits reports whether s consists only of ASCII digits.
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeBookISBNs validates the ISBNs of b, stores them in normalized
+// form and fills in whichever form is missing. When both are supplied they
+// must identify the same book.
+func normalizeBookISBNs(b *Book) error {
+	isbn10, isbn13 := NormalizeISBN(b.ISBN10), NormalizeISBN(b.ISBN13)
+	if isbn10 != "" && !ValidISBN10(isbn10) {
+		return validationErrorf("invalid ISBN-10 %q", b.ISBN10)
+	}
+	if isbn13 != "" && !ValidISBN13(isbn13) {
+		return validationErrorf("invalid ISBN-13 %q", b.ISBN13)
+	}
+	switch {
+	case isbn10 != "" && isbn13 != "":
+		if ISBN10To13(isbn10) != isbn13 {
+			return validationErrorf("ISBN-10 %s and ISBN-13 %s refer to different books", isbn10, isbn13)
+		}
+	case isbn10 != "":
+		isbn13 = ISBN10To13(isbn10)
+	case isbn13 != "":
+		isbn10, _ = ISBN13To10(isbn13)
+	}
+	b.ISBN10, b.ISBN13 = isbn10, isbn13
+	return nil
+}
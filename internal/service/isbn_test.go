@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseISBN covers normalization, checksum validation and conversion
+// between ISBN-10 and ISBN-13.
+func TestParseISBN(t *testing.T) {
+	cases := []struct {
+		in             string
+		isbn10, isbn13 string
+		ok             bool
+	}{
+		{"0-306-40615-2", "0306406152", "9780306406157", true},
+		{"978 0 306 40615 7", "0306406152", "9780306406157", true},
+		{"080442957x", "080442957X", "9780804429573", true},
+		{"9780804429573", "080442957X", "9780804429573", true},
+		{"979-1-234-56789-6", "", "9791234567896", true},
+		{"0306406153", "", "", false},    // bad check digit
+		{"9780306406158", "", "", false}, // bad check digit
+		{"9770306406155", "", "", false}, // not a book prefix
+		{"03064X6152", "", "", false},    // X only allowed last
+		{"12345", "", "", false},
+		{"", "", "", false},
+	}
+	for _, c := range cases {
+		isbn10, isbn13, err := service.ParseISBN(c.in)
+		if !c.ok {
+			assert.ErrorIs(t, err, service.ErrValidation, c.in)
+			continue
+		}
+		assert.NoError(t, err, c.in)
+		assert.Equal(t, c.isbn10, isbn10, c.in)
+		assert.Equal(t, c.isbn13, isbn13, c.in)
+	}
+}
+
+// TestCheckISBN verifies that invalid input is reported without an error.
+func TestCheckISBN(t *testing.T) {
+	check := service.CheckISBN("0-306-40615-2")
+	assert.True(t, check.Valid)
+	assert.Equal(t, "0-306-40615-2", check.Input)
+	assert.Equal(t, "9780306406157", check.ISBN13)
+	assert.Empty(t, check.Reason)
+
+	check = service.CheckISBN("0-306-40615-3")
+	assert.False(t, check.Valid)
+	assert.Empty(t, check.ISBN13)
+	assert.Contains(t, check.Reason, "invalid ISBN-10")
+}
@@ -1,5 +1,11 @@
 package service
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 //
 // ─────────────────────────── DOMAIN MODELS ───────────────────────────
 //
@@ -7,22 +13,96 @@ package service
 // User represents a registered user in the system.
 // Stored in the database using GORM with a unique username.
 type User struct {
-	ID       uint   `gorm:"primaryKey"` // Auto-increment primary key
-	Username string `gorm:"unique"`     // Unique username
-	Password string // Hashed password
+	ID       uint   `gorm:"primaryKey"`    // Auto-increment primary key
+	Username string `gorm:"unique"`        // Unique username
+	Password string `json:"-"`             // Hashed password (never serialized)
+	IsAdmin  bool   `gorm:"default:false"` // Grants access to admin-only endpoints
+
+	Timestamps
 }
 
 // Wishlist represents a list of desired books created by a user.
+// Deleting a wishlist moves it to the trash and hides its books with it;
+// purging it cascades to the books (books.wishlist_id foreign key).
 type Wishlist struct {
 	ID     uint   `gorm:"primaryKey"` // Auto-increment primary key
 	UserID uint   // Reference to the owning user
 	Name   string // Name of the wishlist
+	Books  []Book `gorm:"foreignKey:WishlistID;constraint:OnDelete:CASCADE"` // Books in the wishlist (not loaded by default)
+
+	Timestamps
 }
 
 // Book represents a book stored inside a wishlist.
+// Author mirrors the first entry of Authors so that older clients and rows
+// created before multi-author support keep working.
 type Book struct {
-	ID         uint   `gorm:"primaryKey"` // Auto-increment primary key
-	WishlistID uint   // Reference to the parent wishlist
-	Title      string // Book title
-	Author     string // Book author
+	ID            uint     `gorm:"primaryKey"` // Auto-increment primary key
+	WishlistID    uint     `gorm:"index"`      // Reference to the parent wishlist
+	Title         string   // Book title
+	Author        string   // Primary author
+	Authors       []string `gorm:"serializer:json"` // All authors, in credit order
+	ISBN10        string   `gorm:"column:isbn10"`   // 10-digit ISBN
+	ISBN13        string   `gorm:"column:isbn13"`   // 13-digit ISBN
+	Publisher     string   // Publisher name
+	PublishedDate string   // Publication date as YYYY, YYYY-MM or YYYY-MM-DD
+	PageCount     int      // Number of pages (0 if unknown)
+	Language      string   // Language code, e.g. "en"
+	Categories    []string `gorm:"serializer:json"` // Subjects or genres
+	Description   string   // Synopsis
+	ThumbnailURL  string   `gorm:"column:thumbnail_url"` // Cover image URL
+	Notes         string   // The user's own notes; never filled in from catalogues
+
+	ExternalSource string `gorm:"index:idx_books_external"` // Catalogue the book was imported from, e.g. SourceGoogleBooks
+	ExternalID     string `gorm:"index:idx_books_external"` // Book ID in that catalogue, used to refresh metadata
+
+	EnrichedAt           *time.Time // Last metadata enrichment attempt (nil if never tried)
+	EnrichmentConfidence float64    // How closely the catalogue match fits the book, 0-1
+	AutoFilled           []string   `gorm:"serializer:json"` // Fields filled in by enrichment, by JSON name
+
+	Timestamps
+}
+
+// Timestamps are embedded in every model. GORM sets CreatedAt and UpdatedAt
+// on writes, and Delete only sets DeletedAt: soft-deleted rows are left out
+// of every query that does not ask for them (Unscoped) until they are purged.
+// Rows created before timestamps were recorded have zero CreatedAt and UpdatedAt.
+type Timestamps struct {
+	CreatedAt time.Time      // When the row was created
+	UpdatedAt time.Time      // When the row was last changed
+	DeletedAt gorm.DeletedAt `gorm:"index"` // When the row was moved to the trash (NULL if live)
+}
+
+// Catalogue names stored in Book.ExternalSource.
+const (
+	SourceGoogleBooks = "google_books"
+	SourceOpenLibrary = "open_library"
+)
+
+//
+// ─────────────────────────── UPDATE PAYLOADS ───────────────────────────
+//
+
+// WishlistUpdate describes a partial update of a wishlist.
+// Nil fields are left unchanged.
+type WishlistUpdate struct {
+	Name *string // New wishlist name
+}
+
+// BookUpdate describes a partial update of a book.
+// Nil fields are left unchanged.
+type BookUpdate struct {
+	Title         *string   // New book title
+	Author        *string   // New primary author (ignored when Authors is set)
+	Authors       *[]string // New list of authors
+	ISBN10        *string   // New 10-digit ISBN
+	ISBN13        *string   // New 13-digit ISBN
+	Publisher     *string   // New publisher
+	PublishedDate *string   // New publication date
+	PageCount     *int      // New page count
+	Language      *string   // New language code
+	Categories    *[]string // New categories
+	Description   *string   // New description
+	ThumbnailURL  *string   // New cover image URL
+	Notes         *string   // New notes
 }
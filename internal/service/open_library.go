@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//
+// ─────────────────────────── OPEN LIBRARY SERVICE ───────────────────────────
+//
+
+// Default values applied by NewOpenLibraryService when the options leave them empty.
+const (
+	DefaultOpenLibraryBaseURL   = "https://openlibrary.org"
+	DefaultOpenLibraryCoversURL = "https://covers.openlibrary.org"
+	DefaultOpenLibraryTimeout   = 10 * time.Second
+)
+
+// OpenLibraryOptions configures the Open Library client.
+type OpenLibraryOptions struct {
+	BaseURL   string        // API root (default: DefaultOpenLibraryBaseURL)
+	CoversURL string        // Root of the cover image service (default: DefaultOpenLibraryCoversURL)
+	Client    *http.Client  // HTTP client to use (default: a new client with Timeout)
+	Timeout   time.Duration // Request timeout when Client is nil (default: DefaultOpenLibraryTimeout)
+	UserAgent string        // User-Agent header (default: DefaultGoogleBooksUserAgent)
+}
+
+// openLibraryService searches the Open Library catalogue.
+// Results use the same GoogleBook shape as the Google Books client; IDs are
+// Open Library work IDs such as "OL27448W".
+type openLibraryService struct {
+	baseURL   string
+	coversURL string
+	client    *http.Client
+	userAgent string
+}
+
+// NewOpenLibraryService creates an Open Library client from opts,
+// applying the defaults above to empty fields.
+func NewOpenLibraryService(opts OpenLibraryOptions) BookSearcher {
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultOpenLibraryBaseURL
+	}
+	if opts.CoversURL == "" {
+		opts.CoversURL = DefaultOpenLibraryCoversURL
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultOpenLibraryTimeout
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: opts.Timeout}
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultGoogleBooksUserAgent
+	}
+	return &openLibraryService{
+		baseURL:   strings.TrimRight(opts.BaseURL, "/"),
+		coversURL: strings.TrimRight(opts.CoversURL, "/"),
+		client:    opts.Client,
+		userAgent: opts.UserAgent,
+	}
+}
+
+// openLibraryFields are the document fields requested from /search.json.
+const openLibraryFields = "key,title,author_name,first_publish_year,publisher,language,subject,number_of_pages_median,cover_i,isbn"
+
+// Search queries Open Library's /search.json endpoint.
+// Open Library only indexes books, so printType=magazines is rejected, and
+// only the languages in openLibraryLanguages can be used as a filter.
+func (o *openLibraryService) Search(ctx context.Context, p SearchParams) (*SearchResult, error) {
+	if err := p.normalize(); err != nil {
+		return nil, err
+	}
+	if p.PrintType == "magazines" {
+		return nil, validationErrorf("open library does not index magazines")
+	}
+
+	params := url.Values{
+		"offset": {strconv.Itoa(p.StartIndex)},
+		"limit":  {strconv.Itoa(p.MaxResults)},
+		"fields": {openLibraryFields},
+	}
+	for name, v := range map[string]string{"q": p.Query, "title": p.Title, "author": p.Author, "isbn": p.ISBN, "subject": p.Subject} {
+		if v != "" {
+			params.Set(name, v)
+		}
+	}
+	if p.OrderBy == "newest" {
+		params.Set("sort", "new")
+	}
+	if p.Language != "" {
+		code, ok := openLibraryLanguages[p.Language]
+		if !ok {
+			return nil, validationErrorf("language %q is not supported by open library", p.Language)
+		}
+		params.Set("language", code)
+	}
+
+	var data struct {
+		NumFound int              `json:"numFound"`
+		Docs     []openLibraryDoc `json:"docs"`
+	}
+	if err := o.getJSON(ctx, "/search.json", params, &data); err != nil {
+		return nil, err
+	}
+
+	books := make([]GoogleBook, 0, len(data.Docs))
+	for i := range data.Docs {
+		books = append(books, data.Docs[i].toGoogleBook(o.coversURL))
+	}
+	res := &SearchResult{
+		TotalItems: data.NumFound,
+		StartIndex: p.StartIndex,
+		MaxResults: p.MaxResults,
+		Items:      books,
+	}
+	if next := p.StartIndex + len(books); len(books) > 0 && next < data.NumFound {
+		res.NextStartIndex = next
+		res.HasMore = true
+	}
+	return res, nil
+}
+
+// getJSON sends a GET request for path and decodes a 200 response into dst.
+// Failures use the same error kinds as the Google Books client.
+func (o *openLibraryService) getJSON(ctx context.Context, path string, params url.Values, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", o.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return &Error{Kind: ErrUpstreamUnavailable, Msg: "open library is unreachable", Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		detail := fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return &Error{Kind: ErrUpstreamQuota, Msg: "open library rate limit exceeded, try again later", Err: detail}
+		case resp.StatusCode >= 500:
+			return &Error{Kind: ErrUpstreamUnavailable, Msg: "open library is unavailable", Err: detail}
+		default:
+			return &Error{Kind: ErrUpstreamBadRequest, Msg: "open library rejected the request", Err: detail}
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return &Error{Kind: ErrUpstreamUnavailable, Msg: "open library returned a malformed response", Err: err}
+	}
+	return nil
+}
+
+// openLibraryDoc is the subset of an Open Library search document we use.
+type openLibraryDoc struct {
+	Key              string   `json:"key"`
+	Title            string   `json:"title"`
+	AuthorName       []string `json:"author_name"`
+	FirstPublishYear int      `json:"first_publish_year"`
+	Publisher        []string `json:"publisher"`
+	Language         []string `json:"language"`
+	Subject          []string `json:"subject"`
+	PageCount        int      `json:"number_of_pages_median"`
+	CoverID          int      `json:"cover_i"`
+	ISBN             []string `json:"isbn"`
+}
+
+// maxOpenLibraryCategories caps the subjects copied into Categories;
+// popular works carry hundreds of them.
+const maxOpenLibraryCategories = 5
+
+// toGoogleBook maps an Open Library work into the common volume shape.
+// A work lists the ISBNs of all its editions: the first valid ISBN-13 and
+// ISBN-10 become the identifiers, and all of them are kept for merging.
+func (d *openLibraryDoc) toGoogleBook(coversURL string) GoogleBook {
+	b := GoogleBook{
+		ID:                  strings.TrimPrefix(d.Key, "/works/"),
+		Title:               d.Title,
+		Authors:             d.AuthorName,
+		PageCount:           d.PageCount,
+		IndustryIdentifiers: []IndustryIdentifier{},
+	}
+	if b.Authors == nil {
+		b.Authors = []string{}
+	}
+	if len(b.Authors) > 0 {
+		b.Author = b.Authors[0]
+	}
+	if len(d.Publisher) > 0 {
+		b.Publisher = d.Publisher[0]
+	}
+	if d.FirstPublishYear > 0 {
+		b.PublishedDate = strconv.Itoa(d.FirstPublishYear)
+	}
+	for _, code := range d.Language {
+		if lang := openLibraryLanguageNames[code]; lang != "" {
+			b.Language = lang
+			break
+		}
+	}
+	if len(d.Subject) > 0 {
+		b.Categories = d.Subject[:min(len(d.Subject), maxOpenLibraryCategories)]
+	}
+	if d.CoverID > 0 {
+		b.ThumbnailURL = fmt.Sprintf("%s/b/id/%d-M.jpg", coversURL, d.CoverID)
+	}
+
+	var isbn10, isbn13 string
+	for _, raw := range d.ISBN {
+		c := CheckISBN(raw)
+		if !c.Valid {
+			continue
+		}
+		if isbn13 == "" && len(NormalizeISBN(raw)) == 13 {
+			isbn13 = c.ISBN13
+		}
+		if isbn10 == "" && len(NormalizeISBN(raw)) == 10 {
+			isbn10 = c.ISBN10
+		}
+		b.ISBNs = append(b.ISBNs, c.ISBN13)
+	}
+	if isbn13 != "" {
+		b.IndustryIdentifiers = append(b.IndustryIdentifiers, IndustryIdentifier{Type: "ISBN_13", Identifier: isbn13})
+	}
+	if isbn10 != "" {
+		b.IndustryIdentifiers = append(b.IndustryIdentifiers, IndustryIdentifier{Type: "ISBN_10", Identifier: isbn10})
+	}
+	return b
+}
+
+// openLibraryLanguages maps ISO 639-1 codes to the MARC language codes
+// Open Library uses.
+var openLibraryLanguages = map[string]string{
+	"ar": "ara", "de": "ger", "en": "eng", "es": "spa", "fr": "fre", "it": "ita", "ja": "jpn",
+	"nl": "dut", "pl": "pol", "pt": "por", "ru": "rus", "sv": "swe", "zh": "chi",
+}
+
+// openLibraryLanguageNames is the reverse of openLibraryLanguages.
+var openLibraryLanguageNames = func() map[string]string {
+	m := make(map[string]string, len(openLibraryLanguages))
+	for iso, marc := range openLibraryLanguages {
+		m[marc] = iso
+	}
+	return m
+}()
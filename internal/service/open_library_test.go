@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// openLibraryJSON is a trimmed Open Library /search.json response.
+const openLibraryJSON = `{
+	"numFound": 25,
+	"start": 0,
+	"docs": [
+		{
+			"key": "/works/OL17930368W",
+			"title": "The Go Programming Language",
+			"author_name": ["Alan A. A. Donovan", "Brian W. Kernighan"],
+			"first_publish_year": 2015,
+			"publisher": ["Addison-Wesley"],
+			"language": ["eng"],
+			"subject": ["Go (Computer program language)", "Programming", "Computers", "Software", "Languages", "Extra"],
+			"number_of_pages_median": 380,
+			"cover_i": 8231990,
+			"isbn": ["not-an-isbn", "0134190440", "9780134190440", "9780134190563"]
+		},
+		{"key": "/works/OL1W", "title": "Untitled work"}
+	]
+}`
+
+// TestOpenLibrary_Search verifies the request sent to /search.json and the
+// mapping of Open Library documents into the common volume shape.
+func TestOpenLibrary_Search(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(openLibraryJSON))
+	}))
+	defer srv.Close()
+
+	svc := service.NewOpenLibraryService(service.OpenLibraryOptions{
+		BaseURL:   srv.URL + "/",
+		CoversURL: "https://covers.example.com/",
+		UserAgent: "wishlist-test",
+	})
+	res, err := svc.Search(t.Context(), service.SearchParams{
+		Query: "go", Author: "kernighan", ISBN: "978-0-13-419044-0",
+		MaxResults: 2, OrderBy: "newest", Language: "en",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/search.json", got.URL.Path)
+	q := got.URL.Query()
+	assert.Equal(t, "go", q.Get("q"))
+	assert.Equal(t, "kernighan", q.Get("author"))
+	assert.Equal(t, "9780134190440", q.Get("isbn"))
+	assert.False(t, q.Has("title"))
+	assert.Equal(t, "0", q.Get("offset"))
+	assert.Equal(t, "2", q.Get("limit"))
+	assert.Equal(t, "new", q.Get("sort"))
+	assert.Equal(t, "eng", q.Get("language"))
+	assert.Equal(t, "wishlist-test", got.Header.Get("User-Agent"))
+
+	assert.Equal(t, 25, res.TotalItems)
+	assert.True(t, res.HasMore)
+	assert.Equal(t, 2, res.NextStartIndex)
+	if assert.Len(t, res.Items, 2) {
+		b := res.Items[0]
+		assert.Equal(t, "OL17930368W", b.ID)
+		assert.Equal(t, "Alan A. A. Donovan", b.Author)
+		assert.Equal(t, "Addison-Wesley", b.Publisher)
+		assert.Equal(t, "2015", b.PublishedDate)
+		assert.Equal(t, "en", b.Language)
+		assert.Equal(t, 380, b.PageCount)
+		assert.Len(t, b.Categories, 5)
+		assert.Equal(t, "https://covers.example.com/b/id/8231990-M.jpg", b.ThumbnailURL)
+		assert.Equal(t, []service.IndustryIdentifier{
+			{Type: "ISBN_13", Identifier: "9780134190440"},
+			{Type: "ISBN_10", Identifier: "0134190440"},
+		}, b.IndustryIdentifiers)
+
+		assert.NotNil(t, res.Items[1].Authors)
+		assert.NotNil(t, res.Items[1].IndustryIdentifiers)
+		assert.Empty(t, res.Items[1].ThumbnailURL)
+	}
+}
+
+// TestOpenLibrary_Errors verifies parameter validation and the
+// classification of upstream failures.
+func TestOpenLibrary_Errors(t *testing.T) {
+	status := http.StatusOK
+	body := `{}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	svc := service.NewOpenLibraryService(service.OpenLibraryOptions{BaseURL: srv.URL})
+
+	for _, p := range []service.SearchParams{
+		{},
+		{Query: "go", PrintType: "magazines"},
+		{Query: "go", Language: "xx"},
+	} {
+		_, err := svc.Search(t.Context(), p)
+		assert.ErrorIs(t, err, service.ErrValidation, "%+v", p)
+	}
+
+	cases := []struct {
+		status int
+		body   string
+		want   error
+	}{
+		{http.StatusTooManyRequests, ``, service.ErrUpstreamQuota},
+		{http.StatusBadRequest, `bad query`, service.ErrUpstreamBadRequest},
+		{http.StatusBadGateway, ``, service.ErrUpstreamUnavailable},
+		{http.StatusOK, `{"docs":`, service.ErrUpstreamUnavailable},
+	}
+	for _, c := range cases {
+		status, body = c.status, c.body
+		_, err := svc.Search(t.Context(), service.SearchParams{Query: "go"})
+		assert.ErrorIs(t, err, c.want, "status %d", c.status)
+	}
+}
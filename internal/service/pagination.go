@@ -0,0 +1,88 @@
+package service
+
+import (
+	"slices"
+	"strings"
+)
+
+//
+// ─────────────────────────── LIST PAGINATION ───────────────────────────
+//
+
+// Page size limits for the list endpoints.
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// SortCreated orders a list by creation, oldest first. It is the default
+// sort of every list. IDs are assigned in creation order, so it sorts by ID,
+// which also orders rows created before CreatedAt was recorded.
+const SortCreated = "created"
+
+// ListOptions selects one page of a list.
+// Pages are addressed with opaque cursors rather than offsets, so rows added
+// or removed while a client walks the list never shift or repeat items.
+// Repositories reject a cursor that is malformed or was issued for a different
+// sort with an ErrValidation error.
+type ListOptions struct {
+	Limit   int               // Page size, 1-MaxPageLimit (default: DefaultPageLimit)
+	Cursor  string            // NextCursor of the previous page; empty for the first page
+	Sort    string            // Field to sort by (default: SortCreated)
+	Desc    bool              // Reverse the sort order
+	Filters map[string]string // Case-insensitive substring filters, by field name
+}
+
+// Page is one page of a list.
+type Page[T any] struct {
+	Items      []T    // Items on this page, in sort order
+	NextCursor string // Cursor of the next page; empty on the last page
+}
+
+// listFields describes what a list can be sorted and filtered by.
+type listFields struct {
+	sorts   []string
+	filters []string
+}
+
+// Sort and filter fields accepted by each list.
+var (
+	userListFields     = listFields{sorts: []string{SortCreated, "username"}, filters: []string{"username"}}
+	wishlistListFields = listFields{sorts: []string{SortCreated, "name"}, filters: []string{"name"}}
+	bookListFields     = listFields{sorts: []string{SortCreated, "title", "author"}, filters: []string{"title", "author"}}
+)
+
+// normalize applies defaults, clamps the limit, drops blank filters and
+// rejects sort or filter fields the list does not support.
+// Returns an ErrValidation error describing the first invalid option.
+func (o *ListOptions) normalize(fields listFields) error {
+	switch {
+	case o.Limit < 0:
+		return validationErrorf("limit must be positive")
+	case o.Limit == 0:
+		o.Limit = DefaultPageLimit
+	case o.Limit > MaxPageLimit:
+		o.Limit = MaxPageLimit
+	}
+
+	o.Cursor = strings.TrimSpace(o.Cursor)
+	o.Sort = strings.ToLower(strings.TrimSpace(o.Sort))
+	if o.Sort == "" {
+		o.Sort = SortCreated
+	}
+	if !slices.Contains(fields.sorts, o.Sort) {
+		return validationErrorf("cannot sort by %q (use one of %s)", o.Sort, strings.Join(fields.sorts, ", "))
+	}
+
+	filters := make(map[string]string, len(o.Filters))
+	for name, value := range o.Filters {
+		if !slices.Contains(fields.filters, name) {
+			return validationErrorf("cannot filter by %q (use one of %s)", name, strings.Join(fields.filters, ", "))
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			filters[name] = value
+		}
+	}
+	o.Filters = filters
+	return nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+//
+// ─────────────────────────── PASSWORD HASHING ───────────────────────────
+//
+
+// PasswordHasher hashes and verifies user passwords.
+type PasswordHasher interface {
+	// Hash returns an encoded hash of the password.
+	Hash(password string) (string, error)
+
+	// Verify reports whether the password matches the stored value.
+	// The comparison runs in constant time with respect to the password.
+	Verify(stored, password string) bool
+
+	// NeedsRehash reports whether the stored value was not produced with the
+	// hasher's current parameters (including legacy plaintext values).
+	NeedsRehash(stored string) bool
+}
+
+// bcryptHasher implements PasswordHasher using bcrypt with a fixed cost.
+type bcryptHasher struct {
+	cost  int
+	dummy []byte // hash compared against when the user does not exist
+}
+
+// NewBcryptHasher creates a bcrypt-based PasswordHasher.
+// Costs outside bcrypt's valid range fall back to bcrypt.DefaultCost.
+func NewBcryptHasher(cost int) PasswordHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	dummy, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), cost)
+	return &bcryptHasher{cost: cost, dummy: dummy}
+}
+
+// Hash returns the bcrypt hash of the password.
+// Returns ErrPasswordTooLong if the password exceeds 72 bytes.
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return "", ErrPasswordTooLong
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify compares the password with a bcrypt hash. Values that are not bcrypt
+// hashes are treated as legacy plaintext rows and compared in constant time.
+// An empty stored value still performs a full bcrypt comparison so that
+// lookups for unknown users take as long as real ones.
+func (h *bcryptHasher) Verify(stored, password string) bool {
+	switch {
+	case stored == "":
+		bcrypt.CompareHashAndPassword(h.dummy, []byte(password))
+		return false
+	case isBcryptHash(stored):
+		return bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) == nil
+	default:
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+	}
+}
+
+// NeedsRehash reports whether the stored value is plaintext or was hashed
+// with a different cost than the current one.
+func (h *bcryptHasher) NeedsRehash(stored string) bool {
+	cost, err := bcrypt.Cost([]byte(stored))
+	return err != nil || cost != h.cost
+}
+
+// isBcryptHash reports whether s looks like an encoded bcrypt hash.
+func isBcryptHash(s string) bool {
+	_, err := bcrypt.Cost([]byte(s))
+	return err == nil
+}
+
+//
+// ─────────────────────────── MIGRATION ───────────────────────────
+//
+
+// MigratePlaintextPasswords hashes every stored password that is still in
+// plaintext (i.e. not a bcrypt hash). It is safe to run repeatedly and
+// returns the number of upgraded rows.
+func MigratePlaintextPasswords(ctx context.Context, repo UserRepository, hasher PasswordHasher) (int, error) {
+	users, err := repo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	upgraded := 0
+	for _, u := range users {
+		if isBcryptHash(u.Password) || u.Password == "" {
+			continue
+		}
+		hash, err := hasher.Hash(u.Password)
+		if err != nil {
+			return upgraded, err
+		}
+		if err := repo.UpdatePassword(ctx, u.ID, hash); err != nil {
+			return upgraded, err
+		}
+		upgraded++
+	}
+	return upgraded, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+//
+// ─────────────────────────── TRASH ───────────────────────────
+//
+
+// Default values applied by NewTrashService when the options leave them empty.
+const (
+	DefaultTrashRetention     = 30 * 24 * time.Hour
+	DefaultTrashPurgeInterval = time.Hour
+)
+
+// TrashOptions configures the trash.
+type TrashOptions struct {
+	Retention     time.Duration    // How long deleted items can be restored (default: DefaultTrashRetention)
+	PurgeInterval time.Duration    // How often Run purges expired items (default: DefaultTrashPurgeInterval)
+	Now           func() time.Time // Clock (default: time.Now)
+}
+
+// Trash holds a user's soft-deleted wishlists and books. Books of a deleted
+// wishlist are not listed on their own: they come back with the wishlist.
+type Trash struct {
+	Wishlists []Wishlist    // Deleted wishlists, most recently deleted first
+	Books     []Book        // Deleted books of live wishlists, most recently deleted first
+	Retention time.Duration // How long after DeletedAt an item is purged
+}
+
+// PurgeStats counts the items permanently deleted by a purge.
+type PurgeStats struct {
+	Wishlists int64 // Wishlists removed, not counting their books
+	Books     int64 // Books deleted on their own
+}
+
+// TrashService lists and restores soft-deleted wishlists and books, and
+// permanently deletes them once they have been in the trash for longer
+// than the retention (Purge, Run).
+type TrashService struct {
+	wishlists WishlistRepository
+	books     BookRepository
+
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+}
+
+// NewTrashService creates a trash from opts, applying the defaults above to empty fields.
+func NewTrashService(wishlists WishlistRepository, books BookRepository, opts TrashOptions) *TrashService {
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultTrashRetention
+	}
+	if opts.PurgeInterval <= 0 {
+		opts.PurgeInterval = DefaultTrashPurgeInterval
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &TrashService{
+		wishlists: wishlists,
+		books:     books,
+		retention: opts.Retention,
+		interval:  opts.PurgeInterval,
+		now:       opts.Now,
+	}
+}
+
+// List retrieves the user's deleted wishlists and the deleted books of the
+// user's live wishlists.
+func (s *TrashService) List(ctx context.Context, userID uint) (*Trash, error) {
+	wishlists, err := s.wishlists.ListDeleted(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	books, err := s.books.ListDeleted(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &Trash{Wishlists: wishlists, Books: books, Retention: s.retention}, nil
+}
+
+// RestoreWishlist restores a deleted wishlist of the user together with the
+// books it held when it was deleted. Books deleted on their own stay in the trash.
+// Returns ErrWishlistNotFound if the user's trash has no wishlist with that ID.
+func (s *TrashService) RestoreWishlist(ctx context.Context, userID, wishlistID uint) (*Wishlist, error) {
+	if err := s.wishlists.Restore(ctx, userID, wishlistID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrWishlistNotFound
+		}
+		return nil, err
+	}
+	return getOwnedWishlist(ctx, s.wishlists, userID, wishlistID)
+}
+
+// RestoreBook restores a deleted book into its wishlist, which must be live
+// and owned by the user (restore a deleted wishlist first).
+// Returns ErrBookNotFound if the wishlist has no deleted book with that ID.
+func (s *TrashService) RestoreBook(ctx context.Context, userID, wishlistID, bookID uint) (*Book, error) {
+	if _, err := getOwnedWishlist(ctx, s.wishlists, userID, wishlistID); err != nil {
+		return nil, err
+	}
+	if err := s.books.Restore(ctx, wishlistID, bookID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, err
+	}
+	book, err := s.books.Get(ctx, wishlistID, bookID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrBookNotFound
+	}
+	return book, err
+}
+
+// Purge permanently deletes the wishlists and books that were deleted more
+// than Retention ago, across all users.
+func (s *TrashService) Purge(ctx context.Context) (PurgeStats, error) {
+	before := s.now().Add(-s.retention)
+	var stats PurgeStats
+	var err error
+	if stats.Wishlists, err = s.wishlists.Purge(ctx, before); err != nil {
+		return stats, err
+	}
+	stats.Books, err = s.books.Purge(ctx, before)
+	return stats, err
+}
+
+// Run purges expired items every PurgeInterval until ctx is cancelled,
+// starting immediately. report, if not nil, receives the outcome of each purge.
+func (s *TrashService) Run(ctx context.Context, report func(PurgeStats, error)) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		stats, err := s.Purge(ctx)
+		if report != nil && ctx.Err() == nil {
+			report(stats, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTrashService verifies listing and restoring deleted wishlists and books.
+func TestTrashService(t *testing.T) {
+	owner := newOwner()
+	owner.deleted = map[uint]Wishlist{
+		3: {ID: 3, UserID: 1, Name: "Old"},
+		4: {ID: 4, UserID: 2, Name: "Not mine"},
+	}
+	books := &mockBookRepo{deleted: []Book{{ID: 7, WishlistID: 1, Title: "Dune"}}}
+	trash := NewTrashService(owner, books, TrashOptions{Retention: 48 * time.Hour})
+
+	got, err := trash.List(t.Context(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []Wishlist{{ID: 3, UserID: 1, Name: "Old"}}, got.Wishlists)
+	assert.Equal(t, []Book{{ID: 7, WishlistID: 1, Title: "Dune"}}, got.Books)
+	assert.Equal(t, 48*time.Hour, got.Retention)
+
+	// Wishlists can only be restored from the owner's trash
+	_, err = trash.RestoreWishlist(t.Context(), 1, 4)
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+	w, err := trash.RestoreWishlist(t.Context(), 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "Old", w.Name)
+	_, err = trash.RestoreWishlist(t.Context(), 1, 3)
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+
+	// Books need a live wishlist owned by the caller
+	_, err = trash.RestoreBook(t.Context(), 2, 1, 7)
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+	_, err = trash.RestoreBook(t.Context(), 1, 4, 7)
+	assert.ErrorIs(t, err, ErrWishlistNotFound)
+	_, err = trash.RestoreBook(t.Context(), 1, 1, 8)
+	assert.ErrorIs(t, err, ErrBookNotFound)
+	b, err := trash.RestoreBook(t.Context(), 1, 1, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "Dune", b.Title)
+	assert.Empty(t, books.deleted)
+}
+
+// TestTrashService_Purge verifies the retention cutoff and the background run.
+func TestTrashService_Purge(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	owner := newOwner()
+	owner.deleted = map[uint]Wishlist{3: {ID: 3, UserID: 1}}
+	books := &mockBookRepo{deleted: []Book{{ID: 7, WishlistID: 1}, {ID: 8, WishlistID: 1}}}
+	trash := NewTrashService(owner, books, TrashOptions{
+		Retention:     24 * time.Hour,
+		PurgeInterval: time.Millisecond,
+		Now:           func() time.Time { return now },
+	})
+
+	stats, err := trash.Purge(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, PurgeStats{Wishlists: 1, Books: 2}, stats)
+	assert.Equal(t, now.Add(-24*time.Hour), books.purgeBefore)
+
+	// Run purges immediately and then on every tick until cancelled
+	ctx, cancel := context.WithCancel(t.Context())
+	var mu sync.Mutex
+	runs := 0
+	done := make(chan struct{})
+	go func() {
+		trash.Run(ctx, func(PurgeStats, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if runs++; runs == 3 {
+				cancel()
+			}
+		})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after cancellation")
+	}
+	assert.Equal(t, 3, runs)
+
+	// Defaults apply to empty options
+	trash = NewTrashService(owner, books, TrashOptions{})
+	assert.Equal(t, DefaultTrashRetention, trash.retention)
+	assert.Equal(t, DefaultTrashPurgeInterval, trash.interval)
+}
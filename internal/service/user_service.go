@@ -1,35 +1,99 @@
 package service
 
-import "errors"
-
-// Predefined business-level errors.
-var (
-	// ErrInvalidInput is returned when username or password are empty.
-	ErrInvalidInput = errors.New("username and password cannot be empty")
+import (
+	"context"
+	"errors"
 )
 
 // userService is the concrete implementation of the UserUsecase interface.
 // It contains the business logic for user-related operations.
 type userService struct {
-	repo UserRepository
+	repo   UserRepository
+	hasher PasswordHasher
 }
 
-// NewUserService creates and returns a new UserUsecase implementation.
+// NewUserService creates and returns a new UserUsecase implementation
+// that hashes passwords with bcrypt at the default cost.
 func NewUserService(repo UserRepository) UserUsecase {
-	return &userService{repo: repo}
+	return NewUserServiceWithHasher(repo, NewBcryptHasher(0))
+}
+
+// NewUserServiceWithHasher creates a UserUsecase that uses the given PasswordHasher.
+func NewUserServiceWithHasher(repo UserRepository, hasher PasswordHasher) UserUsecase {
+	return &userService{repo: repo, hasher: hasher}
 }
 
-// Register validates input and registers a new user by delegating to the repository.
+// Register validates input, hashes the password and registers a new user
+// by delegating to the repository.
 // Returns ErrInvalidInput if username or password are empty.
-func (s *userService) Register(username, password string) error {
+func (s *userService) Register(ctx context.Context, username, password string) error {
 	if username == "" || password == "" {
 		return ErrInvalidInput
 	}
-	user := &User{Username: username, Password: password}
-	return s.repo.Add(user)
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	user := &User{Username: username, Password: hash}
+	if err := s.repo.Add(ctx, user); err != nil {
+		if errors.Is(err, ErrConflict) {
+			return ErrUsernameTaken
+		}
+		return err
+	}
+	return nil
 }
 
-// List retrieves all registered users by delegating to the repository.
-func (s *userService) List() ([]User, error) {
-	return s.repo.List()
+// List retrieves one page of registered users, sorted by "created" or
+// "username" and filtered by "username".
+// Returns an ErrValidation error if opts is invalid.
+func (s *userService) List(ctx context.Context, opts ListOptions) (*Page[User], error) {
+	if err := opts.normalize(userListFields); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPage(ctx, opts)
+}
+
+// Get retrieves a single user by ID.
+// Returns ErrUserNotFound if the user does not exist.
+func (s *userService) Get(ctx context.Context, userID uint) (*User, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+// Authenticate verifies the username and password and returns the matching user.
+// Returns ErrInvalidCredentials if the user does not exist or the password is wrong.
+//
+// When the stored hash was produced with outdated parameters (or is a legacy
+// plaintext value), it is transparently replaced with a fresh hash.
+func (s *userService) Authenticate(ctx context.Context, username, password string) (*User, error) {
+	if username == "" || password == "" {
+		return nil, ErrInvalidInput
+	}
+	user, err := s.repo.GetByUsername(ctx, username)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	// Verify against an empty hash for unknown users to keep timing uniform
+	stored := ""
+	if user != nil {
+		stored = user.Password
+	}
+	if !s.hasher.Verify(stored, password) || user == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	// Rehashing is best effort: a failure here must not block a valid login
+	if s.hasher.NeedsRehash(user.Password) {
+		if hash, err := s.hasher.Hash(password); err == nil {
+			if err := s.repo.UpdatePassword(ctx, user.ID, hash); err == nil {
+				user.Password = hash
+			}
+		}
+	}
+	return user, nil
 }
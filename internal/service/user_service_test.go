@@ -1,12 +1,14 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/deividmendozatech-stack/wishlist/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 //
@@ -17,12 +19,12 @@ import (
 // It uses testify/mock to simulate database operations.
 type mockUserRepo struct{ mock.Mock }
 
-func (m *mockUserRepo) Add(u *service.User) error {
+func (m *mockUserRepo) Add(ctx context.Context, u *service.User) error {
 	args := m.Called(u)
 	return args.Error(0)
 }
 
-func (m *mockUserRepo) List() ([]service.User, error) {
+func (m *mockUserRepo) List(ctx context.Context) ([]service.User, error) {
 	args := m.Called()
 	if val, ok := args.Get(0).([]service.User); ok {
 		return val, args.Error(1)
@@ -30,19 +32,74 @@ func (m *mockUserRepo) List() ([]service.User, error) {
 	return nil, args.Error(1)
 }
 
+func (m *mockUserRepo) ListPage(ctx context.Context, opts service.ListOptions) (*service.Page[service.User], error) {
+	args := m.Called(opts)
+	if val, ok := args.Get(0).(*service.Page[service.User]); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockUserRepo) GetByID(ctx context.Context, userID uint) (*service.User, error) {
+	args := m.Called(userID)
+	if val, ok := args.Get(0).(*service.User); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockUserRepo) GetByUsername(ctx context.Context, username string) (*service.User, error) {
+	args := m.Called(username)
+	if val, ok := args.Get(0).(*service.User); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockUserRepo) UpdatePassword(ctx context.Context, userID uint, hash string) error {
+	args := m.Called(userID, hash)
+	return args.Error(0)
+}
+
+func (m *mockUserRepo) SetAdmin(ctx context.Context, username string, admin bool) error {
+	args := m.Called(username, admin)
+	return args.Error(0)
+}
+
+// newTestUserService builds a userService with the cheapest bcrypt cost to keep tests fast.
+func newTestUserService(repo service.UserRepository) service.UserUsecase {
+	return service.NewUserServiceWithHasher(repo, service.NewBcryptHasher(bcrypt.MinCost))
+}
+
+// mustHash returns a bcrypt hash of password with the given cost.
+func mustHash(t *testing.T, password string, cost int) string {
+	t.Helper()
+	h, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(h)
+}
+
 //
 // ──────────────────────────────── TESTS ────────────────────────────────
 //
 
-// TestRegister_Success ensures that a new user can be registered successfully.
+// TestRegister_Success ensures that a new user can be registered successfully
+// and that the stored password is a bcrypt hash, not the raw value.
 func TestRegister_Success(t *testing.T) {
 	repo := new(mockUserRepo)
-	repo.On("Add", mock.AnythingOfType("*service.User")).Return(nil)
+	var stored *service.User
+	repo.On("Add", mock.AnythingOfType("*service.User")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*service.User) }).
+		Return(nil)
 
-	svc := service.NewUserService(repo)
-	err := svc.Register("david", "12345")
+	svc := newTestUserService(repo)
+	err := svc.Register(t.Context(), "david", "12345")
 
 	assert.NoError(t, err)
+	assert.NotEqual(t, "12345", stored.Password)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(stored.Password), []byte("12345")))
 	repo.AssertExpectations(t)
 }
 
@@ -51,8 +108,21 @@ func TestRegister_EmptyFields(t *testing.T) {
 	repo := new(mockUserRepo)
 	svc := service.NewUserService(repo)
 
-	err := svc.Register("", "")
-	assert.Error(t, err)
+	err := svc.Register(t.Context(), "", "")
+	assert.ErrorIs(t, err, service.ErrValidation)
+}
+
+// TestRegister_DuplicateUsername ensures a repository conflict is reported as ErrUsernameTaken.
+func TestRegister_DuplicateUsername(t *testing.T) {
+	repo := new(mockUserRepo)
+	repo.On("Add", mock.AnythingOfType("*service.User")).Return(service.ErrConflict)
+
+	svc := newTestUserService(repo)
+	err := svc.Register(t.Context(), "david", "12345")
+
+	assert.ErrorIs(t, err, service.ErrUsernameTaken)
+	assert.ErrorIs(t, err, service.ErrConflict)
+	repo.AssertExpectations(t)
 }
 
 // TestRegister_AddError simulates a database error when adding a user.
@@ -60,8 +130,8 @@ func TestRegister_AddError(t *testing.T) {
 	repo := new(mockUserRepo)
 	repo.On("Add", mock.AnythingOfType("*service.User")).Return(errors.New("db error"))
 
-	svc := service.NewUserService(repo)
-	err := svc.Register("john", "pwd")
+	svc := newTestUserService(repo)
+	err := svc.Register(t.Context(), "john", "pwd")
 
 	assert.Error(t, err)
 	repo.AssertExpectations(t)
@@ -70,29 +140,129 @@ func TestRegister_AddError(t *testing.T) {
 // TestListUsers_Success validates that the service returns a list of users when the repository succeeds.
 func TestListUsers_Success(t *testing.T) {
 	repo := new(mockUserRepo)
-	repo.On("List").Return([]service.User{
-		{ID: 1, Username: "alice"},
-		{ID: 2, Username: "bob"},
+	opts := service.ListOptions{Limit: 2, Sort: service.SortCreated, Filters: map[string]string{}}
+	repo.On("ListPage", opts).Return(&service.Page[service.User]{
+		Items:      []service.User{{ID: 1, Username: "alice"}, {ID: 2, Username: "bob"}},
+		NextCursor: "next",
 	}, nil)
 
 	svc := service.NewUserService(repo)
-	users, err := svc.List()
+	page, err := svc.List(t.Context(), service.ListOptions{Limit: 2})
 
 	assert.NoError(t, err)
-	assert.Len(t, users, 2)
-	assert.Equal(t, "alice", users[0].Username)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "alice", page.Items[0].Username)
+	assert.Equal(t, "next", page.NextCursor)
 	repo.AssertExpectations(t)
 }
 
 // TestListUsers_Error ensures that repository errors are properly propagated.
 func TestListUsers_Error(t *testing.T) {
 	repo := new(mockUserRepo)
-	repo.On("List").Return(nil, errors.New("db error"))
+	repo.On("ListPage", mock.Anything).Return(nil, errors.New("db error"))
 
 	svc := service.NewUserService(repo)
-	users, err := svc.List()
+	users, err := svc.List(t.Context(), service.ListOptions{})
 
 	assert.Error(t, err)
 	assert.Nil(t, users)
 	repo.AssertExpectations(t)
 }
+
+// TestAuthenticate_Success ensures that matching credentials return the user.
+func TestAuthenticate_Success(t *testing.T) {
+	repo := new(mockUserRepo)
+	hash := mustHash(t, "12345", bcrypt.MinCost)
+	repo.On("GetByUsername", "david").Return(&service.User{ID: 7, Username: "david", Password: hash}, nil)
+
+	svc := newTestUserService(repo)
+	user, err := svc.Authenticate(t.Context(), "david", "12345")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), user.ID)
+	repo.AssertExpectations(t)
+}
+
+// TestAuthenticate_InvalidCredentials checks that unknown users and wrong passwords are rejected.
+func TestAuthenticate_InvalidCredentials(t *testing.T) {
+	repo := new(mockUserRepo)
+	hash := mustHash(t, "12345", bcrypt.MinCost)
+	repo.On("GetByUsername", "david").Return(&service.User{ID: 7, Username: "david", Password: hash}, nil)
+	repo.On("GetByUsername", "ghost").Return(nil, service.ErrNotFound)
+
+	svc := newTestUserService(repo)
+
+	_, err := svc.Authenticate(t.Context(), "david", "wrong")
+	assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+
+	_, err = svc.Authenticate(t.Context(), "ghost", "12345")
+	assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+	repo.AssertExpectations(t)
+}
+
+// TestAuthenticate_RehashOnCostChange verifies that a hash produced with an
+// outdated cost is replaced after a successful login.
+func TestAuthenticate_RehashOnCostChange(t *testing.T) {
+	repo := new(mockUserRepo)
+	oldHash := mustHash(t, "12345", bcrypt.MinCost+1)
+	repo.On("GetByUsername", "david").Return(&service.User{ID: 7, Username: "david", Password: oldHash}, nil)
+	repo.On("UpdatePassword", uint(7), mock.MatchedBy(func(h string) bool {
+		cost, err := bcrypt.Cost([]byte(h))
+		return err == nil && cost == bcrypt.MinCost
+	})).Return(nil)
+
+	svc := newTestUserService(repo)
+	_, err := svc.Authenticate(t.Context(), "david", "12345")
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+// TestAuthenticate_LegacyPlaintext verifies that plaintext rows still log in
+// and are upgraded to a bcrypt hash.
+func TestAuthenticate_LegacyPlaintext(t *testing.T) {
+	repo := new(mockUserRepo)
+	repo.On("GetByUsername", "david").Return(&service.User{ID: 7, Username: "david", Password: "12345"}, nil)
+	repo.On("UpdatePassword", uint(7), mock.AnythingOfType("string")).Return(nil)
+
+	svc := newTestUserService(repo)
+	_, err := svc.Authenticate(t.Context(), "david", "12345")
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+// TestMigratePlaintextPasswords ensures only plaintext rows are rehashed.
+func TestMigratePlaintextPasswords(t *testing.T) {
+	repo := new(mockUserRepo)
+	hash := mustHash(t, "secret", bcrypt.MinCost)
+	repo.On("List").Return([]service.User{
+		{ID: 1, Username: "alice", Password: "plain"},
+		{ID: 2, Username: "bob", Password: hash},
+	}, nil)
+	repo.On("UpdatePassword", uint(1), mock.AnythingOfType("string")).Return(nil)
+
+	n, err := service.MigratePlaintextPasswords(t.Context(), repo, service.NewBcryptHasher(bcrypt.MinCost))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	repo.AssertExpectations(t)
+}
+
+// TestGetUser verifies lookups by ID and the not-found error.
+func TestGetUser(t *testing.T) {
+	repo := new(mockUserRepo)
+	repo.On("GetByID", uint(1)).Return(&service.User{ID: 1, Username: "alice"}, nil)
+	repo.On("GetByID", uint(2)).Return(nil, service.ErrNotFound)
+
+	svc := service.NewUserService(repo)
+
+	user, err := svc.Get(t.Context(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", user.Username)
+
+	_, err = svc.Get(t.Context(), 2)
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+//
+// ─────────────────────────── WISHLIST SEARCH ───────────────────────────
+//
+
+// Result limits for searches across a user's wishlists.
+const (
+	DefaultWishlistSearchLimit = 20
+	MaxWishlistSearchLimit     = 100
+
+	maxWishlistSearchTerms = 10
+)
+
+// BookHit is a book matching a search across a user's wishlists.
+// Highlights holds, by JSON field name ("title", "authors", "description",
+// "notes"), the matching fields as HTML-escaped text with each matched term
+// wrapped in <mark></mark>. Long fields are shortened to the matching passage.
+type BookHit struct {
+	Book       Book              // Matching book
+	Wishlist   Wishlist          // Wishlist holding the book (Books not loaded)
+	Score      float64           // Relevance, higher is better; only meaningful within one search
+	Highlights map[string]string // Matching fields with the terms highlighted
+}
+
+// wishlistSearchService implements the WishlistSearchUsecase interface.
+type wishlistSearchService struct {
+	books BookRepository
+}
+
+// NewWishlistSearchService creates a WishlistSearchUsecase backed by the book repository.
+func NewWishlistSearchService(books BookRepository) WishlistSearchUsecase {
+	return &wishlistSearchService{books: books}
+}
+
+// Search finds the user's books whose title, authors, description or notes
+// contain every word of query, as a word or the start of one, ignoring case
+// and accents. Punctuation only separates words, so the query cannot use
+// search operators. Results are ranked, best first; limit defaults to
+// DefaultWishlistSearchLimit and is capped at MaxWishlistSearchLimit.
+// Returns an ErrValidation error if the query has no words or too many.
+func (s *wishlistSearchService) Search(ctx context.Context, userID uint, query string, limit int) ([]BookHit, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, validationErrorf("search query must contain at least one letter or digit")
+	}
+	if len(terms) > maxWishlistSearchTerms {
+		return nil, validationErrorf("search query cannot have more than %d words", maxWishlistSearchTerms)
+	}
+	switch {
+	case limit < 0:
+		return nil, validationErrorf("limit must be positive")
+	case limit == 0:
+		limit = DefaultWishlistSearchLimit
+	case limit > MaxWishlistSearchLimit:
+		limit = MaxWishlistSearchLimit
+	}
+	return s.books.Search(ctx, userID, terms, limit)
+}
+
+// searchTerms splits a query into lower-cased words of letters and digits.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWishlistSearch verifies that queries are split into plain terms, that
+// the limit is defaulted and capped, and that unusable queries are rejected
+// before reaching the repository.
+func TestWishlistSearch(t *testing.T) {
+	repo := &mockBookRepo{books: []Book{{ID: 1, WishlistID: 3, Title: "The Hobbit"}}}
+	svc := NewWishlistSearchService(repo)
+
+	hits, err := svc.Search(t.Context(), 1, `  Tolkien's "HOBBIT" title:the* -ring `, 0)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, []string{"tolkien", "s", "hobbit", "title", "the", "ring"}, repo.searchTerms)
+	assert.Equal(t, DefaultWishlistSearchLimit, repo.searchLimit)
+
+	_, err = svc.Search(t.Context(), 1, "Cien años", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cien", "años"}, repo.searchTerms)
+	assert.Equal(t, MaxWishlistSearchLimit, repo.searchLimit)
+
+	repo.searchTerms = nil
+	for _, tc := range []struct {
+		query string
+		limit int
+	}{
+		{"", 0},
+		{` "*" - `, 0},
+		{"a b c d e f g h i j k", 0},
+		{"hobbit", -1},
+	} {
+		_, err = svc.Search(t.Context(), 1, tc.query, tc.limit)
+		assert.ErrorIs(t, err, ErrValidation, "%q", tc.query)
+	}
+	assert.Nil(t, repo.searchTerms)
+}
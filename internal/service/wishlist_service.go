@@ -1,5 +1,11 @@
 package service
 
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
 // wishlistService is the concrete implementation of the WishlistUsecase interface.
 // It contains the business logic for managing user wishlists.
 type wishlistService struct {
@@ -12,18 +18,89 @@ func NewWishlistService(r WishlistRepository) WishlistUsecase {
 	return &wishlistService{repo: r}
 }
 
-// Create adds a new wishlist for the given user.
-func (s *wishlistService) Create(userID uint, name string) error {
+// Create adds a new wishlist for the given user and returns it with its ID.
+// Returns an ErrValidation error if the name is blank.
+func (s *wishlistService) Create(ctx context.Context, userID uint, name string) (*Wishlist, error) {
+	name, err := validateWishlistName(name)
+	if err != nil {
+		return nil, err
+	}
 	w := &Wishlist{UserID: userID, Name: name}
-	return s.repo.Add(w)
+	if err := s.repo.Add(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// List retrieves one page of the wishlists that belong to the given user,
+// sorted by "created" or "name" and filtered by "name".
+// Returns an ErrValidation error if opts is invalid.
+func (s *wishlistService) List(ctx context.Context, userID uint, opts ListOptions) (*Page[Wishlist], error) {
+	if err := opts.normalize(wishlistListFields); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPage(ctx, userID, opts)
+}
+
+// Get retrieves a single wishlist, ensuring it belongs to the given user.
+func (s *wishlistService) Get(ctx context.Context, userID, wishlistID uint) (*Wishlist, error) {
+	return getOwnedWishlist(ctx, s.repo, userID, wishlistID)
+}
+
+// Update applies the non-nil fields of upd to a wishlist owned by the user
+// and returns the updated wishlist.
+func (s *wishlistService) Update(ctx context.Context, userID, wishlistID uint, upd WishlistUpdate) (*Wishlist, error) {
+	w, err := getOwnedWishlist(ctx, s.repo, userID, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	if upd.Name != nil {
+		if w.Name, err = validateWishlistName(*upd.Name); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.Update(ctx, w); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrWishlistNotFound
+		}
+		return nil, err
+	}
+	return w, nil
+}
+
+// Delete moves a wishlist to the trash, ensuring it belongs to the given user.
+// Its books are hidden with it and come back when it is restored (see TrashService).
+// Returns ErrWishlistNotFound if the user has no wishlist with that ID.
+func (s *wishlistService) Delete(ctx context.Context, userID, wishlistID uint) error {
+	err := s.repo.Delete(ctx, userID, wishlistID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrWishlistNotFound
+	}
+	return err
 }
 
-// List retrieves all wishlists that belong to the given user.
-func (s *wishlistService) List(userID uint) ([]Wishlist, error) {
-	return s.repo.List(userID)
+// getOwnedWishlist loads a wishlist and ensures it belongs to the user.
+// Returns ErrWishlistNotFound otherwise, whether the wishlist does not exist
+// or belongs to another user.
+func getOwnedWishlist(ctx context.Context, repo WishlistRepository, userID, wishlistID uint) (*Wishlist, error) {
+	w, err := repo.Get(ctx, wishlistID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrWishlistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if w.UserID != userID {
+		return nil, ErrWishlistNotFound
+	}
+	return w, nil
 }
 
-// Delete removes a wishlist by its ID, ensuring it belongs to the given user.
-func (s *wishlistService) Delete(userID, wishlistID uint) error {
-	return s.repo.Delete(userID, wishlistID)
+// validateWishlistName trims the name and rejects blank values.
+func validateWishlistName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", validationErrorf("wishlist name cannot be empty")
+	}
+	return name, nil
 }
@@ -1,64 +1,146 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/deividmendozatech-stack/wishlist/internal/service"
 	"github.com/stretchr/testify/assert"
 )
 
 // mockWishlistRepo is a lightweight mock implementation of service.WishlistRepository.
-// It allows injecting custom behavior for Add, List, and Delete methods in tests.
+// It allows injecting custom behavior for Add, List, Get, Update, and Delete methods in tests.
 type mockWishlistRepo struct {
 	addFn    func(*service.Wishlist) error
-	listFn   func(uint) ([]service.Wishlist, error)
+	listFn   func(uint, service.ListOptions) (*service.Page[service.Wishlist], error)
+	getFn    func(uint) (*service.Wishlist, error)
+	updateFn func(*service.Wishlist) error
 	deleteFn func(uint, uint) error
 }
 
-func (m *mockWishlistRepo) Add(w *service.Wishlist) error {
+func (m *mockWishlistRepo) Add(ctx context.Context, w *service.Wishlist) error {
 	if m.addFn != nil {
 		return m.addFn(w)
 	}
 	return nil
 }
 
-func (m *mockWishlistRepo) List(userID uint) ([]service.Wishlist, error) {
+func (m *mockWishlistRepo) ListPage(ctx context.Context, userID uint, opts service.ListOptions) (*service.Page[service.Wishlist], error) {
 	if m.listFn != nil {
-		return m.listFn(userID)
+		return m.listFn(userID, opts)
 	}
-	return []service.Wishlist{}, nil
+	return &service.Page[service.Wishlist]{}, nil
 }
 
-func (m *mockWishlistRepo) Delete(userID, wishlistID uint) error {
+func (m *mockWishlistRepo) Get(ctx context.Context, wishlistID uint) (*service.Wishlist, error) {
+	if m.getFn != nil {
+		return m.getFn(wishlistID)
+	}
+	return nil, nil
+}
+
+func (m *mockWishlistRepo) Update(ctx context.Context, w *service.Wishlist) error {
+	if m.updateFn != nil {
+		return m.updateFn(w)
+	}
+	return nil
+}
+
+func (m *mockWishlistRepo) Delete(ctx context.Context, userID, wishlistID uint) error {
 	if m.deleteFn != nil {
 		return m.deleteFn(userID, wishlistID)
 	}
 	return nil
 }
 
-// TestWishlistService_Create verifies that a wishlist can be created without errors.
+func (m *mockWishlistRepo) ListDeleted(ctx context.Context, userID uint) ([]service.Wishlist, error) {
+	return nil, nil
+}
+
+func (m *mockWishlistRepo) Restore(ctx context.Context, userID, wishlistID uint) error {
+	return nil
+}
+
+func (m *mockWishlistRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// TestWishlistService_Create verifies that a wishlist can be created and is returned with its ID.
 func TestWishlistService_Create(t *testing.T) {
-	mockRepo := &mockWishlistRepo{}
+	mockRepo := &mockWishlistRepo{
+		addFn: func(w *service.Wishlist) error {
+			w.ID = 5
+			return nil
+		},
+	}
 	svc := service.NewWishlistService(mockRepo)
 
-	err := svc.Create(1, "Mi lista")
+	w, err := svc.Create(t.Context(), 1, " Mi lista ")
 	assert.NoError(t, err)
+	assert.Equal(t, uint(5), w.ID)
+	assert.Equal(t, "Mi lista", w.Name)
+}
+
+// TestWishlistService_CreateEmptyName ensures blank names are rejected as validation errors.
+func TestWishlistService_CreateEmptyName(t *testing.T) {
+	mockRepo := &mockWishlistRepo{
+		addFn: func(*service.Wishlist) error {
+			t.Fatal("repository should not be called")
+			return nil
+		},
+	}
+	svc := service.NewWishlistService(mockRepo)
+
+	_, err := svc.Create(t.Context(), 1, "   ")
+	assert.ErrorIs(t, err, service.ErrValidation)
 }
 
 // TestWishlistService_List verifies that wishlists are correctly retrieved from the repository.
 func TestWishlistService_List(t *testing.T) {
 	mockRepo := &mockWishlistRepo{
-		listFn: func(userID uint) ([]service.Wishlist, error) {
-			return []service.Wishlist{{ID: 1, Name: "Lista de prueba"}}, nil
+		listFn: func(userID uint, opts service.ListOptions) (*service.Page[service.Wishlist], error) {
+			return &service.Page[service.Wishlist]{Items: []service.Wishlist{{ID: 1, Name: "Lista de prueba"}}}, nil
 		},
 	}
 	svc := service.NewWishlistService(mockRepo)
 
-	lists, err := svc.List(1)
+	page, err := svc.List(t.Context(), 1, service.ListOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, lists, 1)
-	assert.Equal(t, "Lista de prueba", lists[0].Name)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "Lista de prueba", page.Items[0].Name)
+}
+
+// TestWishlistService_ListOptions verifies that list options are defaulted,
+// clamped and validated before they reach the repository.
+func TestWishlistService_ListOptions(t *testing.T) {
+	var got service.ListOptions
+	mockRepo := &mockWishlistRepo{
+		listFn: func(userID uint, opts service.ListOptions) (*service.Page[service.Wishlist], error) {
+			got = opts
+			return &service.Page[service.Wishlist]{}, nil
+		},
+	}
+	svc := service.NewWishlistService(mockRepo)
+
+	_, err := svc.List(t.Context(), 1, service.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, service.DefaultPageLimit, got.Limit)
+	assert.Equal(t, service.SortCreated, got.Sort)
+
+	_, err = svc.List(t.Context(), 1, service.ListOptions{Limit: 1000, Sort: " NAME ", Cursor: " abc ", Filters: map[string]string{"name": "  "}})
+	assert.NoError(t, err)
+	assert.Equal(t, service.ListOptions{Limit: service.MaxPageLimit, Sort: "name", Cursor: "abc", Filters: map[string]string{}}, got)
+
+	for _, opts := range []service.ListOptions{
+		{Limit: -1},
+		{Sort: "title"},
+		{Filters: map[string]string{"title": "go"}},
+	} {
+		_, err = svc.List(t.Context(), 1, opts)
+		assert.ErrorIs(t, err, service.ErrValidation, "%+v", opts)
+	}
 }
 
 // TestWishlistService_Delete verifies that a wishlist can be deleted successfully.
@@ -66,7 +148,7 @@ func TestWishlistService_Delete(t *testing.T) {
 	mockRepo := &mockWishlistRepo{}
 	svc := service.NewWishlistService(mockRepo)
 
-	err := svc.Delete(1, 1)
+	err := svc.Delete(t.Context(), 1, 1)
 	assert.NoError(t, err)
 }
 
@@ -79,6 +161,78 @@ func TestWishlistService_DeleteError(t *testing.T) {
 	}
 	svc := service.NewWishlistService(mockRepo)
 
-	err := svc.Delete(1, 1)
+	err := svc.Delete(t.Context(), 1, 1)
 	assert.Error(t, err)
 }
+
+// TestWishlistService_DeleteNotFound ensures a missing wishlist is reported as ErrWishlistNotFound.
+func TestWishlistService_DeleteNotFound(t *testing.T) {
+	mockRepo := &mockWishlistRepo{
+		deleteFn: func(userID, wishlistID uint) error {
+			return service.ErrNotFound
+		},
+	}
+	svc := service.NewWishlistService(mockRepo)
+
+	err := svc.Delete(t.Context(), 1, 999)
+	assert.ErrorIs(t, err, service.ErrWishlistNotFound)
+}
+
+// ownedRepo returns a mock where wishlist 1 belongs to user 1 and wishlist 2 to user 2.
+func ownedRepo() *mockWishlistRepo {
+	return &mockWishlistRepo{
+		getFn: func(id uint) (*service.Wishlist, error) {
+			switch id {
+			case 1:
+				return &service.Wishlist{ID: 1, UserID: 1, Name: "Mine"}, nil
+			case 2:
+				return &service.Wishlist{ID: 2, UserID: 2, Name: "Theirs"}, nil
+			}
+			return nil, service.ErrNotFound
+		},
+	}
+}
+
+// TestWishlistService_Get verifies ownership checks when fetching a single wishlist.
+func TestWishlistService_Get(t *testing.T) {
+	svc := service.NewWishlistService(ownedRepo())
+
+	w, err := svc.Get(t.Context(), 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Mine", w.Name)
+
+	_, err = svc.Get(t.Context(), 1, 2)
+	assert.ErrorIs(t, err, service.ErrWishlistNotFound)
+
+	_, err = svc.Get(t.Context(), 1, 999)
+	assert.ErrorIs(t, err, service.ErrWishlistNotFound)
+}
+
+// TestWishlistService_Update verifies renaming, partial updates and validation.
+func TestWishlistService_Update(t *testing.T) {
+	mockRepo := ownedRepo()
+	var saved *service.Wishlist
+	mockRepo.updateFn = func(w *service.Wishlist) error {
+		saved = w
+		return nil
+	}
+	svc := service.NewWishlistService(mockRepo)
+
+	name := "  Renamed "
+	w, err := svc.Update(t.Context(), 1, 1, service.WishlistUpdate{Name: &name})
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", w.Name)
+	assert.Equal(t, "Renamed", saved.Name)
+
+	// Nil fields are left untouched
+	w, err = svc.Update(t.Context(), 1, 1, service.WishlistUpdate{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Mine", w.Name)
+
+	blank := " "
+	_, err = svc.Update(t.Context(), 1, 1, service.WishlistUpdate{Name: &blank})
+	assert.ErrorIs(t, err, service.ErrValidation)
+
+	_, err = svc.Update(t.Context(), 1, 2, service.WishlistUpdate{Name: &name})
+	assert.ErrorIs(t, err, service.ErrWishlistNotFound)
+}
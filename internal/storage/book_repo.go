@@ -1,8 +1,12 @@
 package storage
 
 import (
+	"context"
+	"time"
+
 	"github.com/deividmendozatech-stack/wishlist/internal/service"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // BookRepo is the GORM-based implementation of the BookRepository interface.
@@ -17,21 +21,149 @@ func NewBookRepo(db *gorm.DB) service.BookRepository {
 }
 
 // Add inserts a new book into the database.
-func (r *BookRepo) Add(b *service.Book) error {
-	return r.db.Create(b).Error
+func (r *BookRepo) Add(ctx context.Context, b *service.Book) error {
+	return translateError(r.db.WithContext(ctx).Create(b).Error)
 }
 
 // List retrieves all books associated with a given wishlist ID.
-func (r *BookRepo) List(wishlistID uint) ([]service.Book, error) {
+func (r *BookRepo) List(ctx context.Context, wishlistID uint) ([]service.Book, error) {
 	var books []service.Book
-	if err := r.db.Where("wishlist_id = ?", wishlistID).Find(&books).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("wishlist_id = ?", wishlistID).Find(&books).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return books, nil
+}
+
+// bookPage describes how books are sorted and filtered by ListPage.
+// The author filter also matches co-authors.
+var bookPage = pageSpec[service.Book]{
+	id: func(b *service.Book) uint { return b.ID },
+	sorts: map[string]sortKey[service.Book]{
+		service.SortCreated: {},
+		"title":             {column: "title", value: func(b *service.Book) string { return b.Title }},
+		"author":            {column: "author", value: func(b *service.Book) string { return b.Author }},
+	},
+	filters: map[string][]string{
+		"title":  {"title"},
+		"author": {"author", "authors"},
+	},
+}
+
+// ListPage retrieves one page of the books in a given wishlist.
+// Returns an ErrValidation error if the cursor is invalid.
+func (r *BookRepo) ListPage(ctx context.Context, wishlistID uint, opts service.ListOptions) (*service.Page[service.Book], error) {
+	return paginate(r.db.WithContext(ctx).Model(&service.Book{}).Where("wishlist_id = ?", wishlistID), bookPage, opts)
+}
+
+// Get retrieves a single book by its ID, ensuring it belongs to the specified wishlist.
+// Returns service.ErrNotFound if no matching book exists.
+func (r *BookRepo) Get(ctx context.Context, wishlistID, bookID uint) (*service.Book, error) {
+	var book service.Book
+	if err := first(r.db.WithContext(ctx).Where("id = ? AND wishlist_id = ?", bookID, wishlistID), &book); err != nil {
 		return nil, err
 	}
+	return &book, nil
+}
+
+// Update persists the editable fields of an existing book and sets b's
+// UpdatedAt to the stored update time.
+// The ID, parent wishlist and creation and deletion times are never modified.
+// Returns service.ErrNotFound if no matching book exists.
+func (r *BookRepo) Update(ctx context.Context, b *service.Book) error {
+	res := r.db.WithContext(ctx).Model(b).
+		Where("id = ? AND wishlist_id = ?", b.ID, b.WishlistID).
+		Select("*").Omit("id", "wishlist_id", "created_at", "deleted_at", clause.Associations).
+		Updates(b)
+	if res.Error != nil {
+		return translateError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateFields persists only the given fields of an existing book, plus its
+// update time, with a single column-scoped UPDATE.
+func (r *BookRepo) UpdateFields(ctx context.Context, b *service.Book, fields ...string) error {
+	res := r.db.WithContext(ctx).Model(b).
+		Where("id = ? AND wishlist_id = ?", b.ID, b.WishlistID).
+		Select(fields).
+		Updates(b)
+	if res.Error != nil {
+		return translateError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// Delete soft-deletes a book by its ID, ensuring it belongs to the specified wishlist.
+// Returns service.ErrNotFound if no matching live book exists.
+func (r *BookRepo) Delete(ctx context.Context, wishlistID, bookID uint) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND wishlist_id = ?", bookID, wishlistID).
+		Delete(&service.Book{})
+	if res.Error != nil {
+		return translateError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// ListDeleted retrieves the soft-deleted books in the live wishlists of a
+// user, most recently deleted first. Books of deleted wishlists are left out.
+func (r *BookRepo) ListDeleted(ctx context.Context, userID uint) ([]service.Book, error) {
+	db := r.db.WithContext(ctx)
+	var books []service.Book
+	err := db.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Where("wishlist_id IN (?)", db.Model(&service.Wishlist{}).Select("id").Where("user_id = ?", userID)).
+		Order("deleted_at DESC, id DESC").
+		Find(&books).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
 	return books, nil
 }
 
-// Delete removes a book by its ID, ensuring it belongs to the specified wishlist.
-func (r *BookRepo) Delete(wishlistID, bookID uint) error {
-	return r.db.Where("id = ? AND wishlist_id = ?", bookID, wishlistID).
-		Delete(&service.Book{}).Error
+// Restore clears the deletion time of a soft-deleted book in the specified wishlist.
+// Returns service.ErrNotFound if no matching deleted book exists.
+func (r *BookRepo) Restore(ctx context.Context, wishlistID, bookID uint) error {
+	res := r.db.WithContext(ctx).Unscoped().Model(&service.Book{}).
+		Where("id = ? AND wishlist_id = ? AND deleted_at IS NOT NULL", bookID, wishlistID).
+		Update("deleted_at", nil)
+	if res.Error != nil {
+		return translateError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// Purge permanently deletes the books soft-deleted before the given time and
+// returns how many were removed.
+func (r *BookRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Unscoped().Where("deleted_at < ?", before.UTC()).Delete(&service.Book{})
+	return res.RowsAffected, translateError(res.Error)
+}
+
+// ListMissingMetadata retrieves up to limit books, across all live wishlists,
+// that lack an ISBN or a cover image and have never been enriched, oldest first.
+func (r *BookRepo) ListMissingMetadata(ctx context.Context, limit int) ([]service.Book, error) {
+	db := r.db.WithContext(ctx)
+	var books []service.Book
+	err := db.
+		Where("wishlist_id IN (?)", db.Model(&service.Wishlist{}).Select("id")).
+		Where("enriched_at IS NULL").
+		Where("(COALESCE(isbn10, '') = '' AND COALESCE(isbn13, '') = '') OR COALESCE(thumbnail_url, '') = ''").
+		Order("id").Limit(limit).
+		Find(&books).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return books, nil
 }
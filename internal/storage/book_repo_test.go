@@ -1,7 +1,9 @@
 package storage
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/deividmendozatech-stack/wishlist/internal/service"
 	"github.com/stretchr/testify/assert"
@@ -33,21 +35,297 @@ func TestBookRepo_AddListDelete(t *testing.T) {
 
 	// Add book
 	b := &service.Book{WishlistID: 1, Title: "Go 101", Author: "Unknown"}
-	err := repo.Add(b)
+	err := repo.Add(t.Context(), b)
 	assert.NoError(t, err)
 
 	// List books
-	books, err := repo.List(1)
+	books, err := repo.List(t.Context(), 1)
 	assert.NoError(t, err)
 	assert.Len(t, books, 1)
 	assert.Equal(t, "Go 101", books[0].Title)
 
 	// Delete book
-	err = repo.Delete(1, books[0].ID)
+	err = repo.Delete(t.Context(), 1, books[0].ID)
 	assert.NoError(t, err)
 
 	// Ensure empty list after deletion
-	books, err = repo.List(1)
+	books, err = repo.List(t.Context(), 1)
 	assert.NoError(t, err)
 	assert.Empty(t, books)
 }
+
+// TestBookRepo_DeleteNotFound verifies that deleting a missing book,
+// or a book from the wrong wishlist, reports service.ErrNotFound.
+func TestBookRepo_DeleteNotFound(t *testing.T) {
+	db := setupBookTestDB(t)
+	repo := NewBookRepo(db)
+
+	b := &service.Book{WishlistID: 1, Title: "Go 101", Author: "Unknown"}
+	assert.NoError(t, repo.Add(t.Context(), b))
+
+	// Nonexistent ID
+	assert.ErrorIs(t, repo.Delete(t.Context(), 1, 999), service.ErrNotFound)
+
+	// Existing book, wrong wishlist
+	assert.ErrorIs(t, repo.Delete(t.Context(), 2, b.ID), service.ErrNotFound)
+
+	// Deleting twice: first succeeds, second reports not found
+	assert.NoError(t, repo.Delete(t.Context(), 1, b.ID))
+	assert.ErrorIs(t, repo.Delete(t.Context(), 1, b.ID), service.ErrNotFound)
+}
+
+// TestBookRepo_Trash verifies that deleted books are listed per user, only
+// for live wishlists, and can be restored until they are purged.
+func TestBookRepo_Trash(t *testing.T) {
+	db := setupBookTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&service.Wishlist{}))
+	for _, w := range []service.Wishlist{{ID: 1, UserID: 1}, {ID: 2, UserID: 1}, {ID: 3, UserID: 2}} {
+		assert.NoError(t, db.Create(&w).Error)
+	}
+	repo := NewBookRepo(db)
+	for _, b := range []service.Book{
+		{WishlistID: 1, Title: "Dune"},
+		{WishlistID: 1, Title: "Emma"},
+		{WishlistID: 2, Title: "Ulysses"},
+		{WishlistID: 3, Title: "Theirs"},
+	} {
+		assert.NoError(t, repo.Add(t.Context(), &b))
+		assert.NoError(t, repo.Delete(t.Context(), b.WishlistID, b.ID))
+	}
+	// Books of a deleted wishlist come back with it, not on their own
+	assert.NoError(t, db.Delete(&service.Wishlist{}, 2).Error)
+
+	deleted, err := repo.ListDeleted(t.Context(), 1)
+	assert.NoError(t, err)
+	var titles []string
+	for _, b := range deleted {
+		titles = append(titles, b.Title)
+		assert.True(t, b.DeletedAt.Valid)
+	}
+	assert.Equal(t, []string{"Emma", "Dune"}, titles)
+
+	assert.ErrorIs(t, repo.Restore(t.Context(), 2, deleted[0].ID), service.ErrNotFound)
+	assert.NoError(t, repo.Restore(t.Context(), 1, deleted[0].ID))
+	assert.ErrorIs(t, repo.Restore(t.Context(), 1, deleted[0].ID), service.ErrNotFound)
+	got, err := repo.Get(t.Context(), 1, deleted[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Emma", got.Title)
+
+	n, err := repo.Purge(t.Context(), time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+	n, err = repo.Purge(t.Context(), time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.ErrorIs(t, repo.Restore(t.Context(), 1, deleted[1].ID), service.ErrNotFound)
+}
+
+// TestBookRepo_GetUpdate verifies fetching a single book and updating it
+// without moving it to another wishlist.
+func TestBookRepo_GetUpdate(t *testing.T) {
+	db := setupBookTestDB(t)
+	repo := NewBookRepo(db)
+
+	b := &service.Book{WishlistID: 1, Title: "Go 101", Author: "Unknown"}
+	assert.NoError(t, repo.Add(t.Context(), b))
+
+	got, err := repo.Get(t.Context(), 1, b.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Go 101", got.Title)
+
+	_, err = repo.Get(t.Context(), 2, b.ID)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+
+	time.Sleep(time.Millisecond)
+	got.Title = "Go 102"
+	got.Author = ""
+	assert.NoError(t, repo.Update(t.Context(), got))
+	assert.True(t, got.UpdatedAt.After(b.UpdatedAt), "update time set on the passed value")
+	updatedAt := got.UpdatedAt
+
+	got, err = repo.Get(t.Context(), 1, b.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Go 102", got.Title)
+	assert.Empty(t, got.Author)
+	assert.False(t, got.CreatedAt.IsZero(), "creation time kept")
+	assert.True(t, got.UpdatedAt.Equal(updatedAt))
+
+	assert.ErrorIs(t, repo.Update(t.Context(), &service.Book{ID: b.ID, WishlistID: 2, Title: "X"}), service.ErrNotFound)
+}
+
+// TestBookRepo_UpdateFields verifies that only the given fields are written,
+// so that concurrent changes to other fields are kept.
+func TestBookRepo_UpdateFields(t *testing.T) {
+	db := setupBookTestDB(t)
+	repo := NewBookRepo(db)
+
+	b := &service.Book{WishlistID: 1, Title: "Dune"}
+	assert.NoError(t, repo.Add(t.Context(), b))
+	stale := *b
+
+	b.Notes = "Edited meanwhile"
+	assert.NoError(t, repo.Update(t.Context(), b))
+
+	stale.Publisher = "Chilton"
+	stale.AutoFilled = []string{"publisher"}
+	assert.NoError(t, repo.UpdateFields(t.Context(), &stale, "Publisher", "AutoFilled"))
+	assert.False(t, stale.UpdatedAt.Before(b.UpdatedAt), "update time refreshed")
+
+	got, err := repo.Get(t.Context(), 1, b.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Chilton", got.Publisher)
+	assert.Equal(t, []string{"publisher"}, got.AutoFilled)
+	assert.Equal(t, "Edited meanwhile", got.Notes)
+	assert.True(t, got.UpdatedAt.Equal(stale.UpdatedAt))
+
+	stale.WishlistID = 2
+	assert.ErrorIs(t, repo.UpdateFields(t.Context(), &stale, "Publisher"), service.ErrNotFound)
+}
+
+// TestBookRepo_Metadata verifies that list fields round-trip through their
+// JSON columns together with the rest of the metadata.
+func TestBookRepo_Metadata(t *testing.T) {
+	db := setupBookTestDB(t)
+	repo := NewBookRepo(db)
+
+	b := &service.Book{
+		WishlistID:   1,
+		Title:        "Dune",
+		Author:       "Frank Herbert",
+		Authors:      []string{"Frank Herbert"},
+		ISBN13:       "9780441172719",
+		PageCount:    412,
+		Categories:   []string{"Fiction", "Science Fiction"},
+		ThumbnailURL: "https://example.com/dune.jpg",
+	}
+	assert.NoError(t, repo.Add(t.Context(), b))
+
+	got, err := repo.Get(t.Context(), 1, b.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Frank Herbert"}, got.Authors)
+	assert.Equal(t, []string{"Fiction", "Science Fiction"}, got.Categories)
+	assert.Equal(t, "9780441172719", got.ISBN13)
+	assert.Equal(t, 412, got.PageCount)
+	assert.Equal(t, "https://example.com/dune.jpg", got.ThumbnailURL)
+}
+
+// TestBookRepo_ListMissingMetadata verifies that only books lacking an ISBN or
+// a cover, and never enriched before, are returned in ID order. Books in the
+// trash, or in a wishlist in the trash, are skipped.
+func TestBookRepo_ListMissingMetadata(t *testing.T) {
+	db := setupBookTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&service.Wishlist{}))
+	for _, w := range []service.Wishlist{{ID: 1, UserID: 1}, {ID: 2, UserID: 1}, {ID: 3, UserID: 1}} {
+		assert.NoError(t, db.Create(&w).Error)
+	}
+	repo := NewBookRepo(db)
+
+	enriched := time.Now()
+	for _, b := range []service.Book{
+		{WishlistID: 1, Title: "No ISBN", ThumbnailURL: "https://example.com/a.jpg"},
+		{WishlistID: 1, Title: "Complete", ISBN13: "9780306406157", ThumbnailURL: "https://example.com/b.jpg"},
+		{WishlistID: 2, Title: "No cover", ISBN10: "0306406152"},
+		{WishlistID: 2, Title: "Attempted", EnrichedAt: &enriched},
+		{WishlistID: 2, Title: "Nothing"},
+		{WishlistID: 2, Title: "Deleted"},
+		{WishlistID: 3, Title: "In deleted wishlist"},
+	} {
+		assert.NoError(t, repo.Add(t.Context(), &b))
+	}
+	// Rows written before the metadata columns existed hold NULLs
+	assert.NoError(t, db.Exec("UPDATE books SET isbn10 = NULL, isbn13 = NULL WHERE title = ?", "Nothing").Error)
+	assert.NoError(t, db.Where("title = ?", "Deleted").Delete(&service.Book{}).Error)
+	assert.NoError(t, db.Delete(&service.Wishlist{}, 3).Error)
+
+	books, err := repo.ListMissingMetadata(t.Context(), 10)
+	assert.NoError(t, err)
+	var titles []string
+	for _, b := range books {
+		titles = append(titles, b.Title)
+	}
+	assert.Equal(t, []string{"No ISBN", "No cover", "Nothing"}, titles)
+
+	books, err = repo.ListMissingMetadata(t.Context(), 2)
+	assert.NoError(t, err)
+	assert.Len(t, books, 2)
+}
+
+// TestBookRepo_ListPage verifies keyset pagination over every sort, in both
+// directions, together with the title and author filters.
+func TestBookRepo_ListPage(t *testing.T) {
+	db := setupBookTestDB(t)
+	repo := NewBookRepo(db)
+	for _, b := range []service.Book{
+		{WishlistID: 1, Title: "dune", Author: "Frank Herbert"},
+		{WishlistID: 1, Title: "Anathem", Author: "Neal Stephenson"},
+		{WishlistID: 1, Title: "Cryptonomicon", Author: "Neal Stephenson"},
+		{WishlistID: 1, Title: "Good Omens", Author: "Terry Pratchett", Authors: []string{"Terry Pratchett", "Neil Gaiman"}},
+		{WishlistID: 1, Title: "100% Go", Author: "Anon"},
+		{WishlistID: 2, Title: "Other wishlist", Author: "Anon"},
+	} {
+		assert.NoError(t, repo.Add(t.Context(), &b))
+	}
+
+	// walk collects the titles of every page of size 2
+	walk := func(opts service.ListOptions) []string {
+		opts.Limit = 2
+		var titles []string
+		for i := 0; i < 10; i++ {
+			page, err := repo.ListPage(t.Context(), 1, opts)
+			if !assert.NoError(t, err) {
+				return nil
+			}
+			assert.LessOrEqual(t, len(page.Items), 2)
+			for _, b := range page.Items {
+				titles = append(titles, b.Title)
+			}
+			if page.NextCursor == "" {
+				return titles
+			}
+			opts.Cursor = page.NextCursor
+		}
+		t.Fatal("pagination did not terminate")
+		return nil
+	}
+
+	assert.Equal(t, []string{"dune", "Anathem", "Cryptonomicon", "Good Omens", "100% Go"}, walk(service.ListOptions{Sort: service.SortCreated}))
+	assert.Equal(t, []string{"100% Go", "Anathem", "Cryptonomicon", "dune", "Good Omens"}, walk(service.ListOptions{Sort: "title"}))
+	assert.Equal(t, []string{"Good Omens", "dune", "Cryptonomicon", "Anathem", "100% Go"}, walk(service.ListOptions{Sort: "title", Desc: true}))
+	// Equal authors are ordered by ID, in the direction of the sort
+	assert.Equal(t, []string{"100% Go", "dune", "Anathem", "Cryptonomicon", "Good Omens"}, walk(service.ListOptions{Sort: "author"}))
+	assert.Equal(t, []string{"Good Omens", "Cryptonomicon", "Anathem", "dune", "100% Go"}, walk(service.ListOptions{Sort: "author", Desc: true}))
+
+	// Filters are case-insensitive substrings, match co-authors and treat wildcards literally
+	assert.Equal(t, []string{"Anathem", "Cryptonomicon"}, walk(service.ListOptions{Sort: "title", Filters: map[string]string{"author": "STEPHENSON"}}))
+	assert.Equal(t, []string{"Good Omens"}, walk(service.ListOptions{Filters: map[string]string{"author": "gaiman"}}))
+	assert.Equal(t, []string{"100% Go"}, walk(service.ListOptions{Filters: map[string]string{"title": "0%"}}))
+	assert.Empty(t, walk(service.ListOptions{Filters: map[string]string{"title": "_"}}))
+
+	// Cursors only resume the sort they were issued for
+	page, err := repo.ListPage(t.Context(), 1, service.ListOptions{Limit: 1, Sort: "title"})
+	assert.NoError(t, err)
+	_, err = repo.ListPage(t.Context(), 1, service.ListOptions{Sort: "author", Cursor: page.NextCursor})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	_, err = repo.ListPage(t.Context(), 1, service.ListOptions{Sort: "title", Desc: true, Cursor: page.NextCursor})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	_, err = repo.ListPage(t.Context(), 1, service.ListOptions{Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, service.ErrValidation)
+}
+
+// TestBookRepo_Context verifies that queries run with the caller's context, so
+// a cancelled request or an expired deadline stops them.
+func TestBookRepo_Context(t *testing.T) {
+	db := setupBookTestDB(t)
+	repo := NewBookRepo(db)
+	assert.NoError(t, repo.Add(t.Context(), &service.Book{WishlistID: 1, Title: "Go 101"}))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err := repo.List(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	ctx, cancel = context.WithDeadline(t.Context(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = repo.Get(ctx, 1, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,342 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"gorm.io/gorm"
+)
+
+//
+// ─────────────────────────── FULL-TEXT SEARCH ───────────────────────────
+//
+
+// Books are searched through books_fts, an FTS5 table holding a copy of the
+// searchable text of every book under the book's ID. Triggers on books keep
+// it in sync, including deletes cascaded from wishlists. Books in the trash,
+// or in a wishlist in the trash, stay indexed until they are purged and are
+// filtered out by the queries instead.
+//
+// FTS5 is only compiled into go-sqlite3 with the sqlite_fts5 build tag. When
+// it is missing the table is not created and searches fall back to LIKE
+// matching: substrings instead of word prefixes, no accent folding, and a
+// coarser ranking computed in Go.
+
+// Weights of title, authors, description and notes in the ranking.
+var searchWeights = [4]float64{10, 5, 1, 2}
+
+// snippetTokens is the length, in words, of description and notes highlights.
+const snippetTokens = 24
+
+// Highlight markers used between the query and markHighlight. They are
+// control characters so that they cannot clash with stored text.
+const (
+	markStart = "\x02"
+	markEnd   = "\x03"
+)
+
+// bookSearchAuthors turns the JSON authors array of the row named by the
+// argument (new, books) into plain text, falling back to the single author.
+const bookSearchAuthors = `COALESCE(CASE WHEN json_valid(%[1]s.authors) THEN
+	(SELECT group_concat(value, ', ') FROM json_each(%[1]s.authors)) END, %[1]s.author)`
+
+// bookSearchTriggers keep books_fts in sync with books.
+var bookSearchTriggers = map[string]string{
+	"books_fts_insert": `CREATE TRIGGER books_fts_insert AFTER INSERT ON books BEGIN
+		INSERT INTO books_fts(rowid, title, authors, description, notes)
+		VALUES (new.id, new.title, ` + fmt.Sprintf(bookSearchAuthors, "new") + `, new.description, new.notes);
+	END`,
+	"books_fts_update": `CREATE TRIGGER books_fts_update AFTER UPDATE ON books BEGIN
+		DELETE FROM books_fts WHERE rowid = old.id;
+		INSERT INTO books_fts(rowid, title, authors, description, notes)
+		VALUES (new.id, new.title, ` + fmt.Sprintf(bookSearchAuthors, "new") + `, new.description, new.notes);
+	END`,
+	"books_fts_delete": `CREATE TRIGGER books_fts_delete AFTER DELETE ON books BEGIN
+		DELETE FROM books_fts WHERE rowid = old.id;
+	END`,
+}
+
+// migrateBookSearch creates books_fts and its triggers when FTS5 is available.
+//
+// The index is rebuilt from books whenever the table or a trigger had to be
+// created: on the first run, and after a migration that recreated the books
+// table (which drops its triggers). Otherwise it is left as is, so this is
+// cheap to run on every start.
+func migrateBookSearch(db *gorm.DB) error {
+	var fts5 bool
+	if err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5).Error; err != nil {
+		return err
+	}
+	if !fts5 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var existing []string
+		if err := tx.Raw("SELECT name FROM sqlite_master WHERE name = 'books_fts' OR (type = 'trigger' AND tbl_name = 'books')").
+			Scan(&existing).Error; err != nil {
+			return err
+		}
+		has := func(name string) bool { return slices.Contains(existing, name) }
+
+		rebuild := false
+		if !has("books_fts") {
+			if err := tx.Exec(`CREATE VIRTUAL TABLE books_fts USING fts5(
+				title, authors, description, notes,
+				tokenize = 'unicode61 remove_diacritics 2')`).Error; err != nil {
+				return err
+			}
+			rebuild = true
+		}
+		for name, ddl := range bookSearchTriggers {
+			if !has(name) {
+				if err := tx.Exec(ddl).Error; err != nil {
+					return err
+				}
+				rebuild = true
+			}
+		}
+		if !rebuild {
+			return nil
+		}
+		if err := tx.Exec("DELETE FROM books_fts").Error; err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO books_fts(rowid, title, authors, description, notes)
+			SELECT id, title, ` + fmt.Sprintf(bookSearchAuthors, "books") + `, description, notes FROM books`).Error
+	})
+}
+
+// bookHitRow is one row of a search query.
+type bookHitRow struct {
+	service.Book
+	WishlistName string
+	Score        float64
+
+	TitleHighlight       string
+	AuthorsHighlight     string
+	DescriptionHighlight string
+	NotesHighlight       string
+}
+
+// toHit converts the row into a service.BookHit, keeping only the
+// highlights that contain a match.
+func (r bookHitRow) toHit() service.BookHit {
+	hit := service.BookHit{
+		Book:       r.Book,
+		Wishlist:   service.Wishlist{ID: r.WishlistID, Name: r.WishlistName},
+		Score:      r.Score,
+		Highlights: map[string]string{},
+	}
+	for name, text := range map[string]string{
+		"title":       r.TitleHighlight,
+		"authors":     r.AuthorsHighlight,
+		"description": r.DescriptionHighlight,
+		"notes":       r.NotesHighlight,
+	} {
+		if text = markHighlight(text); text != "" {
+			hit.Highlights[name] = text
+		}
+	}
+	return hit
+}
+
+// Search retrieves up to limit books, across the wishlists of a user, that
+// match every term, best match first. See the section comment for how the
+// FTS5 and fallback searches differ.
+func (r *BookRepo) Search(ctx context.Context, userID uint, terms []string, limit int) ([]service.BookHit, error) {
+	db := r.db.WithContext(ctx)
+	var indexed int64
+	if err := db.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'books_fts'").
+		Scan(&indexed).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	var rows []bookHitRow
+	var err error
+	if indexed > 0 {
+		rows, err = searchIndexed(db, userID, terms, limit)
+	} else {
+		rows, err = searchUnindexed(db, userID, terms, limit)
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+	hits := make([]service.BookHit, len(rows))
+	for i, row := range rows {
+		hits[i] = row.toHit()
+	}
+	return hits, nil
+}
+
+// searchIndexed runs the search against books_fts, ranked by BM25.
+// Every term is quoted, so it is matched literally, and used as a prefix.
+func searchIndexed(db *gorm.DB, userID uint, terms []string, limit int) ([]bookHitRow, error) {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(t, `"`, `""`) + `"*`
+	}
+	rank := fmt.Sprintf("bm25(books_fts, %g, %g, %g, %g)", searchWeights[0], searchWeights[1], searchWeights[2], searchWeights[3])
+
+	var rows []bookHitRow
+	err := db.Raw(`SELECT books.*, wishlists.name AS wishlist_name, -`+rank+` AS score,
+			highlight(books_fts, 0, char(2), char(3)) AS title_highlight,
+			highlight(books_fts, 1, char(2), char(3)) AS authors_highlight,
+			snippet(books_fts, 2, char(2), char(3), '…', ?) AS description_highlight,
+			snippet(books_fts, 3, char(2), char(3), '…', ?) AS notes_highlight
+		FROM books_fts
+		JOIN books ON books.id = books_fts.rowid
+		JOIN wishlists ON wishlists.id = books.wishlist_id
+		WHERE books_fts MATCH ? AND wishlists.user_id = ?
+			AND books.deleted_at IS NULL AND wishlists.deleted_at IS NULL
+		ORDER BY `+rank+`, books.id
+		LIMIT ?`,
+		snippetTokens, snippetTokens, strings.Join(quoted, " "), userID, limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// searchUnindexed matches every term as a substring with LIKE, then scores
+// and highlights the matches in Go.
+func searchUnindexed(db *gorm.DB, userID uint, terms []string, limit int) ([]bookHitRow, error) {
+	q := db.Table("books").
+		Select("books.*, wishlists.name AS wishlist_name").
+		Joins("JOIN wishlists ON wishlists.id = books.wishlist_id").
+		Where("wishlists.user_id = ?", userID).
+		Where("books.deleted_at IS NULL AND wishlists.deleted_at IS NULL")
+	for _, t := range terms {
+		p := "%" + escapeLike(t) + "%"
+		q = q.Where(`(books.title LIKE ? ESCAPE '\' OR books.authors LIKE ? ESCAPE '\' OR books.author LIKE ? ESCAPE '\'
+			OR books.description LIKE ? ESCAPE '\' OR books.notes LIKE ? ESCAPE '\')`, p, p, p, p, p)
+	}
+	var rows []bookHitRow
+	if err := q.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		authors := strings.Join(row.Authors, ", ")
+		if authors == "" {
+			authors = row.Author
+		}
+		fields := [4]*string{&row.TitleHighlight, &row.AuthorsHighlight, &row.DescriptionHighlight, &row.NotesHighlight}
+		for j, text := range [4]string{row.Title, authors, row.Description, row.Notes} {
+			maxLen := 0
+			if j >= 2 {
+				maxLen = snippetTokens * 8
+			}
+			*fields[j] = highlightTerms(text, terms, maxLen)
+			for _, t := range terms {
+				if len(matchSpans(text, []string{t})) > 0 {
+					row.Score += searchWeights[j]
+				}
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Score != rows[j].Score {
+			return rows[i].Score > rows[j].Score
+		}
+		return rows[i].ID < rows[j].ID
+	})
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows, nil
+}
+
+// highlightTerms marks every case-insensitive occurrence of the terms in
+// text. If maxLen is positive and text is longer, only a passage of about
+// maxLen bytes around the first occurrence is kept, with ellipses where text
+// was cut. Returns "" if no term occurs.
+func highlightTerms(text string, terms []string, maxLen int) string {
+	spans := matchSpans(text, terms)
+	if len(spans) == 0 {
+		return ""
+	}
+	start, end := 0, len(text)
+	if maxLen > 0 && len(text) > maxLen {
+		start = max(0, spans[0][0]-maxLen/4)
+		for start > 0 && !utf8.RuneStart(text[start]) {
+			start--
+		}
+		end = min(len(text), start+maxLen)
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, sp := range spans {
+		if sp[0] < pos || sp[1] > end {
+			continue
+		}
+		b.WriteString(text[pos:sp[0]] + markStart + text[sp[0]:sp[1]] + markEnd)
+		pos = sp[1]
+	}
+	b.WriteString(text[pos:end])
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// matchSpans returns the byte ranges of the case-insensitive occurrences of
+// the terms in text, each extended to the end of its word as the FTS5
+// highlights are, sorted and merged where they overlap.
+func matchSpans(text string, terms []string) [][2]int {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		lower = text // Lower-casing changed byte offsets; match case-sensitively
+	}
+	var spans [][2]int
+	for _, t := range terms {
+		for i := 0; t != ""; {
+			j := strings.Index(lower[i:], t)
+			if j < 0 {
+				break
+			}
+			end := i + j + len(t)
+			for end < len(text) {
+				r, size := utf8.DecodeRuneInString(text[end:])
+				if !unicode.IsLetter(r) && !unicode.IsNumber(r) {
+					break
+				}
+				end += size
+			}
+			spans = append(spans, [2]int{i + j, end})
+			i = end
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	merged := spans[:0]
+	for _, sp := range spans {
+		if n := len(merged); n > 0 && sp[0] <= merged[n-1][1] {
+			merged[n-1][1] = max(merged[n-1][1], sp[1])
+			continue
+		}
+		merged = append(merged, sp)
+	}
+	return merged
+}
+
+// markHighlight HTML-escapes a highlighted text and turns the markers into
+// <mark> elements. Returns "" if the text has no marked match.
+func markHighlight(text string) string {
+	if !strings.Contains(text, markStart) {
+		return ""
+	}
+	return strings.NewReplacer(markStart, "<mark>", markEnd, "</mark>").Replace(html.EscapeString(text))
+}
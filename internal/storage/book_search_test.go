@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// setupSearchTestDB migrates a database holding two wishlists of user 1 and
+// one of user 2, and returns it with the ID of every book by title.
+// Without the sqlite_fts5 build tag, searches use the LIKE fallback.
+func setupSearchTestDB(t *testing.T) (*gorm.DB, map[string]uint) {
+	db := setupFileTestDB(t)
+	if err := Migrate(db); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	lists := []*service.Wishlist{{UserID: 1, Name: "Fantasy"}, {UserID: 1, Name: "Gifts"}, {UserID: 2, Name: "Theirs"}}
+	for _, w := range lists {
+		assert.NoError(t, db.Create(w).Error)
+	}
+	books := []service.Book{
+		{WishlistID: lists[0].ID, Title: "The Hobbit", Author: "J. R. R. Tolkien", Authors: []string{"J. R. R. Tolkien"},
+			Description: "A hobbit is swept into a quest to reclaim a treasure guarded by a dragon."},
+		{WishlistID: lists[0].ID, Title: "Dragon Rider", Author: "Cornelia Funke", Authors: []string{"Cornelia Funke"}},
+		{WishlistID: lists[1].ID, Title: "The Silmarillion", Author: "J. R. R. Tolkien", Authors: []string{"J. R. R. Tolkien", "Christopher Tolkien"},
+			Notes: "<b>Birthday</b> present for Ana"},
+		{WishlistID: lists[1].ID, Title: "Cien años de soledad", Author: "Gabriel García Márquez", Authors: []string{"Gabriel García Márquez"}},
+		{WishlistID: lists[2].ID, Title: "The Lord of the Rings", Author: "J. R. R. Tolkien", Authors: []string{"J. R. R. Tolkien"}},
+	}
+	ids := map[string]uint{}
+	for _, b := range books {
+		assert.NoError(t, db.Create(&b).Error)
+		ids[b.Title] = b.ID
+	}
+	return db, ids
+}
+
+// hasSearchIndex reports whether Migrate created the FTS5 index.
+func hasSearchIndex(db *gorm.DB) bool {
+	var n int64
+	db.Raw("SELECT count(*) FROM sqlite_master WHERE name = 'books_fts'").Scan(&n)
+	return n > 0
+}
+
+// hitTitles returns the titles of the hits, in order.
+func hitTitles(hits []service.BookHit) []string {
+	titles := make([]string, len(hits))
+	for i, h := range hits {
+		titles[i] = h.Book.Title
+	}
+	return titles
+}
+
+// TestBookRepo_Search verifies matching across a user's wishlists, ranking,
+// highlights and that the index follows changes to books.
+func TestBookRepo_Search(t *testing.T) {
+	db, ids := setupSearchTestDB(t)
+	repo := NewBookRepo(db)
+	t.Logf("full-text index: %v", hasSearchIndex(db))
+
+	// Only the caller's books match, from every wishlist, with the wishlist attached
+	hits, err := repo.Search(t.Context(), 1, []string{"tolkien"}, 10)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"The Hobbit", "The Silmarillion"}, hitTitles(hits))
+	for _, h := range hits {
+		assert.Contains(t, []string{"Fantasy", "Gifts"}, h.Wishlist.Name)
+		assert.Equal(t, h.Book.WishlistID, h.Wishlist.ID)
+		assert.Contains(t, h.Highlights["authors"], "<mark>Tolkien</mark>")
+		assert.NotContains(t, h.Highlights, "title")
+	}
+
+	// Every term must match; prefixes match
+	hits, err = repo.Search(t.Context(), 1, []string{"hob", "tolk"}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"The Hobbit"}, hitTitles(hits))
+	assert.Equal(t, "The <mark>Hobbit</mark>", hits[0].Highlights["title"])
+
+	// Title matches rank above description matches
+	hits, err = repo.Search(t.Context(), 1, []string{"dragon"}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Dragon Rider", "The Hobbit"}, hitTitles(hits))
+	assert.Greater(t, hits[0].Score, hits[1].Score)
+	assert.Contains(t, hits[1].Highlights["description"], "<mark>dragon</mark>")
+
+	hits, err = repo.Search(t.Context(), 1, []string{"dragon"}, 1)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+
+	// Notes are searched and highlights are HTML-escaped
+	hits, err = repo.Search(t.Context(), 1, []string{"birthday"}, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, hits, 1) {
+		assert.Equal(t, "&lt;b&gt;<mark>Birthday</mark>&lt;/b&gt; present for Ana", hits[0].Highlights["notes"])
+	}
+
+	if hasSearchIndex(db) {
+		// Accents are ignored
+		hits, err = repo.Search(t.Context(), 1, []string{"garcia", "anos"}, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Cien años de soledad"}, hitTitles(hits))
+	}
+
+	// Updates, deletes (including whole wishlists) and restores are reflected
+	assert.NoError(t, db.Model(&service.Book{}).Where("id = ?", ids["Dragon Rider"]).Update("title", "Reckless").Error)
+	hits, err = repo.Search(t.Context(), 1, []string{"dragon"}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"The Hobbit"}, hitTitles(hits))
+
+	assert.NoError(t, repo.Delete(t.Context(), hits[0].Book.WishlistID, ids["The Hobbit"]))
+	assert.NoError(t, db.Where("name = ?", "Gifts").Delete(&service.Wishlist{}).Error)
+	hits, err = repo.Search(t.Context(), 1, []string{"tolkien"}, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+
+	assert.NoError(t, repo.Restore(t.Context(), wishlistIDByName(t, db, "Fantasy"), ids["The Hobbit"]))
+	hits, err = repo.Search(t.Context(), 1, []string{"tolkien"}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"The Hobbit"}, hitTitles(hits))
+}
+
+// wishlistIDByName returns the ID of the named wishlist.
+func wishlistIDByName(t *testing.T, db *gorm.DB, name string) uint {
+	var w service.Wishlist
+	assert.NoError(t, db.Where("name = ?", name).First(&w).Error)
+	return w.ID
+}
+
+// TestMigrate_RebuildsSearchIndex verifies that books stored while the index
+// or its triggers were missing become searchable after the next Migrate.
+func TestMigrate_RebuildsSearchIndex(t *testing.T) {
+	db, _ := setupSearchTestDB(t)
+	if !hasSearchIndex(db) {
+		t.Skip("FTS5 not compiled in (build with -tags sqlite_fts5)")
+	}
+	repo := NewBookRepo(db)
+
+	assert.NoError(t, db.Exec("DROP TRIGGER books_fts_insert").Error)
+	assert.NoError(t, db.Create(&service.Book{WishlistID: 1, Title: "Unfinished Tales"}).Error)
+	hits, err := repo.Search(t.Context(), 1, []string{"unfinished"}, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+
+	assert.NoError(t, Migrate(db))
+	hits, err = repo.Search(t.Context(), 1, []string{"unfinished"}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+}
+
+// TestHighlightTerms verifies the highlights built by the LIKE fallback.
+func TestHighlightTerms(t *testing.T) {
+	assert.Equal(t, "", highlightTerms("The Hobbit", []string{"dragon"}, 0))
+	assert.Equal(t, "\x02The\x03 \x02Hobbit\x03", highlightTerms("The Hobbit", []string{"hob", "the", "he"}, 0))
+
+	long := strings.Repeat("filler ", 40) + "a dragon appears " + strings.Repeat("filler ", 40)
+	got := highlightTerms(long, []string{"dragon"}, 80)
+	assert.True(t, strings.HasPrefix(got, "…") && strings.HasSuffix(got, "…"), got)
+	assert.Contains(t, got, "\x02dragon\x03")
+	assert.Less(t, len(got), 100)
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CacheEntry is a persisted Google Books response, stored as JSON.
+type CacheEntry struct {
+	Key       string    `gorm:"primaryKey"`
+	Value     []byte    `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"index;not null"`
+}
+
+// TableName keeps the cache table name explicit and separate from domain tables.
+func (CacheEntry) TableName() string { return "google_books_cache" }
+
+// CacheRepo is the GORM-based implementation of the CacheStore interface.
+type CacheRepo struct {
+	db *gorm.DB
+}
+
+// NewCacheRepo creates a new CacheRepo instance using the provided GORM DB connection.
+func NewCacheRepo(db *gorm.DB) service.CacheStore {
+	return &CacheRepo{db: db}
+}
+
+// Get retrieves a cached value by key, including already expired entries.
+// Returns service.ErrNotFound if the key is not stored.
+func (r *CacheRepo) Get(ctx context.Context, key string) ([]byte, time.Time, error) {
+	var e CacheEntry
+	if err := first(r.db.WithContext(ctx).Where("key = ?", key), &e); err != nil {
+		return nil, time.Time{}, err
+	}
+	return e.Value, e.ExpiresAt, nil
+}
+
+// Set inserts or replaces the value stored under key. expiresAt is stored in
+// UTC, since SQLite compares timestamps as text.
+func (r *CacheRepo) Set(ctx context.Context, key string, value []byte, expiresAt time.Time) error {
+	e := CacheEntry{Key: key, Value: value, ExpiresAt: expiresAt.UTC()}
+	return translateError(r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&e).Error)
+}
+
+// DeleteExpired removes the entries that expired before now.
+func (r *CacheRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("expires_at <= ?", now.UTC()).Delete(&CacheEntry{})
+	return res.RowsAffected, translateError(res.Error)
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestCacheRepo verifies storing, replacing, reading and expiring cache entries.
+func TestCacheRepo(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := db.AutoMigrate(&CacheEntry{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	repo := NewCacheRepo(db)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	_, _, err = repo.Get(t.Context(), "volume:vol1")
+	assert.ErrorIs(t, err, service.ErrNotFound)
+
+	assert.NoError(t, repo.Set(t.Context(), "volume:vol1", []byte(`{"id":"old"}`), now.Add(time.Minute)))
+	assert.NoError(t, repo.Set(t.Context(), "volume:vol1", []byte(`{"id":"vol1"}`), now.Add(time.Hour)))
+	assert.NoError(t, repo.Set(t.Context(), "volume:vol2", []byte(`{"id":"vol2"}`), now.Add(-time.Minute)))
+
+	value, expiresAt, err := repo.Get(t.Context(), "volume:vol1")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"vol1"}`, string(value))
+	assert.True(t, expiresAt.Equal(now.Add(time.Hour)))
+
+	n, err := repo.DeleteExpired(t.Context(), now)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	_, _, err = repo.Get(t.Context(), "volume:vol2")
+	assert.ErrorIs(t, err, service.ErrNotFound)
+
+	// Times in other zones are compared as instants, not as text
+	east := time.FixedZone("UTC+5", 5*60*60)
+	assert.NoError(t, repo.Set(t.Context(), "volume:vol3", []byte(`{"id":"vol3"}`), now.Add(-time.Minute).In(east)))
+	n, err = repo.DeleteExpired(t.Context(), now.In(time.FixedZone("UTC-5", -5*60*60)))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	_, _, err = repo.Get(t.Context(), "volume:vol1")
+	assert.NoError(t, err)
+}
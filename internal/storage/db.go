@@ -1,13 +1,22 @@
 package storage
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 // newConnection (privada)
 func newConnection(path string) (*gorm.DB, error) {
-	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(withForeignKeys(path)), &gorm.Config{
+		// SQLite compares timestamps as text, so they are all stored in UTC
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -18,3 +27,57 @@ func newConnection(path string) (*gorm.DB, error) {
 func InitDB(path string) (*gorm.DB, error) {
 	return newConnection(path)
 }
+
+// withForeignKeys appends the go-sqlite3 option that enables the
+// foreign_keys pragma on every pooled connection (SQLite disables it by default).
+func withForeignKeys(path string) string {
+	if strings.Contains(path, "_foreign_keys=") || strings.Contains(path, "_fk=") {
+		return path
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + "_foreign_keys=on"
+}
+
+// ErrOrphanedBooks is returned by Migrate when books reference deleted
+// wishlists, which would violate the books.wishlist_id foreign key.
+var ErrOrphanedBooks = errors.New("orphaned books found; run `go run ./cmd/cleanup` before starting the API")
+
+// Migrate creates or updates the tables for all domain models, the
+// persistent Google Books cache and the book search index.
+//
+// Adding the books → wishlists foreign key rebuilds the books table, which
+// fails if orphaned rows exist, so they are detected up front and reported
+// with ErrOrphanedBooks instead of an opaque constraint error.
+func Migrate(db *gorm.DB) error {
+	m := db.Migrator()
+	if m.HasTable(&service.Book{}) && m.HasTable(&service.Wishlist{}) {
+		orphans, err := FindOrphanBooks(db)
+		if err != nil {
+			return err
+		}
+		if len(orphans) > 0 {
+			return fmt.Errorf("%w (%d rows)", ErrOrphanedBooks, len(orphans))
+		}
+	}
+	if err := db.AutoMigrate(&service.User{}, &service.Wishlist{}, &service.Book{}, &CacheEntry{}); err != nil {
+		return err
+	}
+	if err := backfillBookAuthors(db); err != nil {
+		return err
+	}
+	return migrateBookSearch(db)
+}
+
+// backfillBookAuthors populates the JSON authors column for rows created
+// before books supported multiple authors, using the single author column.
+// Rows that already have authors are left untouched, so it is safe to run
+// on every start. This is a data migration, not an edit, so updated_at is
+// left as is.
+func backfillBookAuthors(db *gorm.DB) error {
+	return db.Unscoped().Model(&service.Book{}).
+		Where("(authors IS NULL OR authors IN ('', 'null')) AND author <> ''").
+		UpdateColumn("authors", gorm.Expr("json_array(author)")).Error
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// setupFileTestDB opens a temporary on-disk database through InitDB so that
+// the foreign_keys pragma is applied exactly as in production.
+func setupFileTestDB(t *testing.T) *gorm.DB {
+	db, err := InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	return db
+}
+
+// TestMigrate_ForeignKeyCascade verifies that, after Migrate, the database
+// itself cascades wishlist deletions to books and rejects orphan inserts.
+func TestMigrate_ForeignKeyCascade(t *testing.T) {
+	db := setupFileTestDB(t)
+	assert.NoError(t, Migrate(db))
+
+	w := &service.Wishlist{UserID: 1, Name: "Lista"}
+	assert.NoError(t, db.Create(w).Error)
+	assert.NoError(t, db.Create(&service.Book{WishlistID: w.ID, Title: "Go 101"}).Error)
+
+	// Deleting the parent row directly relies on ON DELETE CASCADE
+	assert.NoError(t, db.Unscoped().Delete(&service.Wishlist{}, w.ID).Error)
+	var count int64
+	db.Unscoped().Model(&service.Book{}).Count(&count)
+	assert.Zero(t, count)
+
+	// Books must reference an existing wishlist
+	err := NewBookRepo(db).Add(t.Context(), &service.Book{WishlistID: 999, Title: "Orphan"})
+	assert.ErrorIs(t, err, service.ErrValidation)
+}
+
+// TestOrphanBooks verifies detection and removal of books whose wishlist is
+// gone, and that Migrate refuses to add the constraint while they exist.
+func TestOrphanBooks(t *testing.T) {
+	db := setupBookTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&service.Wishlist{}))
+
+	w := &service.Wishlist{UserID: 1, Name: "Lista"}
+	assert.NoError(t, db.Create(w).Error)
+	assert.NoError(t, db.Create(&service.Book{WishlistID: w.ID, Title: "Kept"}).Error)
+	assert.NoError(t, db.Create(&service.Book{WishlistID: 42, Title: "Orphan"}).Error)
+
+	assert.ErrorIs(t, Migrate(db), ErrOrphanedBooks)
+
+	orphans, err := FindOrphanBooks(db)
+	assert.NoError(t, err)
+	assert.Len(t, orphans, 1)
+	assert.Equal(t, "Orphan", orphans[0].Title)
+
+	n, err := DeleteOrphanBooks(db)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	orphans, err = FindOrphanBooks(db)
+	assert.NoError(t, err)
+	assert.Empty(t, orphans)
+	assert.NoError(t, Migrate(db))
+}
+
+// TestMigrate_LegacyBooks verifies that a books table from before the
+// metadata columns existed is upgraded in place and that the single author
+// column is copied into the authors list without touching updated_at.
+func TestMigrate_LegacyBooks(t *testing.T) {
+	db := setupFileTestDB(t)
+	assert.NoError(t, db.Exec("CREATE TABLE wishlists (id integer PRIMARY KEY AUTOINCREMENT, user_id integer, name text)").Error)
+	assert.NoError(t, db.Exec("CREATE TABLE books (id integer PRIMARY KEY AUTOINCREMENT, title text, author text, description text, wishlist_id integer)").Error)
+	assert.NoError(t, db.Exec("INSERT INTO wishlists (user_id, name) VALUES (1, 'Lista')").Error)
+	assert.NoError(t, db.Exec("INSERT INTO books (title, author, description, wishlist_id) VALUES ('Go 101', 'Anon', 'Intro', 1), ('Untitled', '', '', 1)").Error)
+
+	assert.NoError(t, Migrate(db))
+	assert.NoError(t, Migrate(db)) // idempotent
+
+	books, err := NewBookRepo(db).List(t.Context(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, books, 2)
+	assert.Equal(t, []string{"Anon"}, books[0].Authors)
+	assert.True(t, books[0].UpdatedAt.IsZero(), "backfill is not an edit")
+	assert.Equal(t, "Intro", books[0].Description)
+	assert.Empty(t, books[1].Authors)
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// translateError converts GORM and SQLite errors into the service error kinds
+// (service.ErrNotFound, service.ErrConflict, service.ErrValidation) so that
+// callers never depend on driver-specific errors. Unknown errors pass through.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return service.ErrNotFound
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return service.ErrConflict
+	}
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return service.NewError(service.ErrValidation, "referenced record does not exist")
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			return service.ErrConflict
+		case sqlite3.ErrConstraintForeignKey:
+			return service.NewError(service.ErrValidation, "referenced record does not exist")
+		}
+	}
+	return err
+}
+
+// first loads a single row matching the query into dest.
+// Returns service.ErrNotFound when no row matches.
+func first(q *gorm.DB, dest any) error {
+	res := q.Limit(1).Find(dest)
+	if res.Error != nil {
+		return translateError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"gorm.io/gorm"
+)
+
+//
+// ─────────────────────────── MAINTENANCE ───────────────────────────
+//
+
+// orphanBooks scopes a query to books whose wishlist no longer exists.
+// Soft-deleted rows are included on both sides: books of a wishlist in the
+// trash are not orphans, and orphans are removed even if they were in the
+// trash themselves. Unscoped also keeps the query valid on databases that
+// predate the deleted_at columns.
+func orphanBooks(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Model(&service.Book{}).
+		Where("wishlist_id IS NULL OR wishlist_id NOT IN (?)", db.Unscoped().Model(&service.Wishlist{}).Select("id"))
+}
+
+// FindOrphanBooks returns books whose parent wishlist no longer exists.
+//
+// Params:
+//   - db: the GORM database connection
+//
+// Returns:
+//   - []service.Book: the orphaned books
+//   - error: any database error encountered
+func FindOrphanBooks(db *gorm.DB) ([]service.Book, error) {
+	var books []service.Book
+	if err := orphanBooks(db).Find(&books).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return books, nil
+}
+
+// DeleteOrphanBooks permanently removes books whose parent wishlist no longer exists.
+//
+// Params:
+//   - db: the GORM database connection
+//
+// Returns:
+//   - int64: number of deleted books
+//   - error: any database error encountered
+func DeleteOrphanBooks(db *gorm.DB) (int64, error) {
+	res := orphanBooks(db).Delete(&service.Book{})
+	return res.RowsAffected, translateError(res.Error)
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/deividmendozatech-stack/wishlist/internal/service"
+	"gorm.io/gorm"
+)
+
+//
+// ─────────────────────────── KEYSET PAGINATION ───────────────────────────
+//
+
+// sortKey maps a sort field to the column it orders by.
+type sortKey[T any] struct {
+	column string          // Text column compared case-insensitively; empty orders by ID alone
+	value  func(*T) string // Value of column for a row, stored in cursors
+}
+
+// pageSpec describes how a list is sorted, filtered and paged.
+// Every sort breaks ties by ID, so the order is total and a cursor holding
+// the last row's sort value and ID pins down where the next page starts.
+type pageSpec[T any] struct {
+	id      func(*T) uint
+	sorts   map[string]sortKey[T]
+	filters map[string][]string // Filter field -> columns it matches (any of)
+}
+
+// pageCursor is the decoded form of service.ListOptions.Cursor.
+type pageCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v,omitempty"`
+	ID    uint   `json:"id"`
+}
+
+// encode returns the cursor as an opaque URL-safe string.
+func (c pageCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor parses a cursor issued for the given sort.
+// Returns an ErrValidation error if it is malformed or was issued for another sort.
+func decodeCursor(s, sort string, desc bool) (pageCursor, error) {
+	var c pageCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(b, &c)
+	}
+	if err != nil || c.ID == 0 {
+		return c, service.NewError(service.ErrValidation, "invalid cursor")
+	}
+	if c.Sort != sort || c.Desc != desc {
+		return c, service.NewError(service.ErrValidation, "cursor does not match the requested sort")
+	}
+	return c, nil
+}
+
+// paginate applies the filters, sort and cursor of opts to q and loads one page.
+// One extra row is fetched to find out whether another page follows.
+// A zero limit or sort falls back to the service defaults.
+func paginate[T any](q *gorm.DB, spec pageSpec[T], opts service.ListOptions) (*service.Page[T], error) {
+	if opts.Limit <= 0 {
+		opts.Limit = service.DefaultPageLimit
+	}
+	if opts.Sort == "" {
+		opts.Sort = service.SortCreated
+	}
+	key, ok := spec.sorts[opts.Sort]
+	if !ok {
+		return nil, service.NewError(service.ErrValidation, "unsupported sort "+opts.Sort)
+	}
+
+	for name, value := range opts.Filters {
+		columns, ok := spec.filters[name]
+		if !ok {
+			return nil, service.NewError(service.ErrValidation, "unsupported filter "+name)
+		}
+		pattern := "%" + escapeLike(value) + "%"
+		conds := make([]string, len(columns))
+		args := make([]any, len(columns))
+		for i, col := range columns {
+			conds[i], args[i] = col+` LIKE ? ESCAPE '\'`, pattern
+		}
+		q = q.Where("("+strings.Join(conds, " OR ")+")", args...)
+	}
+
+	dir, cmp := "ASC", ">"
+	if opts.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	if opts.Cursor != "" {
+		c, err := decodeCursor(opts.Cursor, opts.Sort, opts.Desc)
+		if err != nil {
+			return nil, err
+		}
+		if key.column == "" {
+			q = q.Where("id "+cmp+" ?", c.ID)
+		} else {
+			col := key.column + " COLLATE NOCASE"
+			q = q.Where("("+col+" "+cmp+" ? OR ("+col+" = ? AND id "+cmp+" ?))", c.Value, c.Value, c.ID)
+		}
+	}
+	if key.column != "" {
+		q = q.Order(key.column + " COLLATE NOCASE " + dir)
+	}
+	q = q.Order("id " + dir)
+
+	var items []T
+	if err := q.Limit(opts.Limit + 1).Find(&items).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	page := &service.Page[T]{Items: items}
+	if len(items) > opts.Limit {
+		page.Items = items[:opts.Limit]
+		last := &page.Items[opts.Limit-1]
+		c := pageCursor{Sort: opts.Sort, Desc: opts.Desc, ID: spec.id(last)}
+		if key.value != nil {
+			c.Value = key.value(last)
+		}
+		page.NextCursor = c.encode()
+	}
+	return page, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally
+// (with ESCAPE '\').
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
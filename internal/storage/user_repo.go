@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"context"
+
 	"github.com/deividmendozatech-stack/wishlist/internal/service"
 	"gorm.io/gorm"
 )
@@ -22,9 +24,9 @@ func NewUserRepo(db *gorm.DB) service.UserRepository {
 //   - u: pointer to a User entity
 //
 // Returns:
-//   - error: if the database operation fails
-func (r *UserRepo) Add(u *service.User) error {
-	return r.db.Create(u).Error
+//   - error: service.ErrConflict if the username exists, or any database error
+func (r *UserRepo) Add(ctx context.Context, u *service.User) error {
+	return translateError(r.db.WithContext(ctx).Create(u).Error)
 }
 
 // List retrieves all users from the database.
@@ -32,10 +34,90 @@ func (r *UserRepo) Add(u *service.User) error {
 // Returns:
 //   - []service.User: slice of all users
 //   - error: if the database operation fails
-func (r *UserRepo) List() ([]service.User, error) {
+func (r *UserRepo) List(ctx context.Context) ([]service.User, error) {
 	var users []service.User
-	if err := r.db.Find(&users).Error; err != nil {
-		return nil, err
+	if err := r.db.WithContext(ctx).Find(&users).Error; err != nil {
+		return nil, translateError(err)
 	}
 	return users, nil
 }
+
+// userPage describes how users are sorted and filtered by ListPage.
+var userPage = pageSpec[service.User]{
+	id: func(u *service.User) uint { return u.ID },
+	sorts: map[string]sortKey[service.User]{
+		service.SortCreated: {},
+		"username":          {column: "username", value: func(u *service.User) string { return u.Username }},
+	},
+	filters: map[string][]string{"username": {"username"}},
+}
+
+// ListPage retrieves one page of users.
+//
+// Params:
+//   - opts: normalized page, sort and filter options
+//
+// Returns:
+//   - *service.Page[service.User]: the users on the page and the next cursor
+//   - error: service.ErrValidation for an invalid cursor, or any database error
+func (r *UserRepo) ListPage(ctx context.Context, opts service.ListOptions) (*service.Page[service.User], error) {
+	return paginate(r.db.WithContext(ctx).Model(&service.User{}), userPage, opts)
+}
+
+// GetByID retrieves a single user by ID.
+//
+// Params:
+//   - userID: the ID of the user
+//
+// Returns:
+//   - *service.User: the matching user
+//   - error: service.ErrNotFound if no user matches, or any database error
+func (r *UserRepo) GetByID(ctx context.Context, userID uint) (*service.User, error) {
+	var user service.User
+	if err := first(r.db.WithContext(ctx).Where("id = ?", userID), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByUsername retrieves a single user by username.
+//
+// Params:
+//   - username: the username to look up
+//
+// Returns:
+//   - *service.User: the matching user
+//   - error: service.ErrNotFound if no user matches, or any database error
+func (r *UserRepo) GetByUsername(ctx context.Context, username string) (*service.User, error) {
+	var user service.User
+	if err := first(r.db.WithContext(ctx).Where("username = ?", username), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdatePassword replaces the stored password hash of a user.
+//
+// Params:
+//   - userID: the ID of the user
+//   - hash: the new encoded password hash
+//
+// Returns:
+//   - error: if the database operation fails
+func (r *UserRepo) UpdatePassword(ctx context.Context, userID uint, hash string) error {
+	return translateError(r.db.WithContext(ctx).Model(&service.User{}).Where("id = ?", userID).
+		Update("password", hash).Error)
+}
+
+// SetAdmin grants or revokes admin privileges for a username.
+//
+// Params:
+//   - username: the username to update
+//   - admin: whether the user should be an admin
+//
+// Returns:
+//   - error: if the database operation fails
+func (r *UserRepo) SetAdmin(ctx context.Context, username string, admin bool) error {
+	return translateError(r.db.WithContext(ctx).Model(&service.User{}).Where("username = ?", username).
+		Update("is_admin", admin).Error)
+}
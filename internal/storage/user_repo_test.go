@@ -31,13 +31,88 @@ func TestUserRepo_AddAndList(t *testing.T) {
 
 	// Create user
 	user := &service.User{Username: "david", Password: "12345"}
-	err := repo.Add(user)
+	err := repo.Add(t.Context(), user)
 	assert.NoError(t, err)
 	assert.NotZero(t, user.ID)
 
 	// List users
-	users, err := repo.List()
+	users, err := repo.List(t.Context())
 	assert.NoError(t, err)
 	assert.Len(t, users, 1)
 	assert.Equal(t, "david", users[0].Username)
+
+	// List one page of users, filtered by username
+	assert.NoError(t, repo.Add(t.Context(), &service.User{Username: "alice", Password: "12345"}))
+	page, err := repo.ListPage(t.Context(), service.ListOptions{Limit: 1, Sort: "username", Filters: map[string]string{"username": "a"}})
+	assert.NoError(t, err)
+	if assert.Len(t, page.Items, 1) {
+		assert.Equal(t, "alice", page.Items[0].Username)
+	}
+	page, err = repo.ListPage(t.Context(), service.ListOptions{Limit: 1, Sort: "username", Filters: map[string]string{"username": "a"}, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	if assert.Len(t, page.Items, 1) {
+		assert.Equal(t, "david", page.Items[0].Username)
+	}
+	assert.Empty(t, page.NextCursor)
+}
+
+// TestUserRepo_GetByUsername verifies lookups by username,
+// including service.ErrNotFound for unknown users.
+func TestUserRepo_GetByUsername(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepo(db)
+
+	assert.NoError(t, repo.Add(t.Context(), &service.User{Username: "david", Password: "12345"}))
+
+	user, err := repo.GetByUsername(t.Context(), "david")
+	assert.NoError(t, err)
+	assert.NotNil(t, user)
+	assert.Equal(t, "david", user.Username)
+
+	user, err = repo.GetByUsername(t.Context(), "ghost")
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	assert.Nil(t, user)
+}
+
+// TestUserRepo_UpdatePassword verifies that a user's stored hash can be replaced.
+func TestUserRepo_UpdatePassword(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepo(db)
+
+	user := &service.User{Username: "david", Password: "old"}
+	assert.NoError(t, repo.Add(t.Context(), user))
+	assert.NoError(t, repo.UpdatePassword(t.Context(), user.ID, "new-hash"))
+
+	got, err := repo.GetByUsername(t.Context(), "david")
+	assert.NoError(t, err)
+	assert.Equal(t, "new-hash", got.Password)
+}
+
+// TestUserRepo_GetByIDAndSetAdmin verifies lookups by ID and toggling admin privileges.
+func TestUserRepo_GetByIDAndSetAdmin(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepo(db)
+
+	user := &service.User{Username: "david", Password: "hash"}
+	assert.NoError(t, repo.Add(t.Context(), user))
+	assert.NoError(t, repo.SetAdmin(t.Context(), "david", true))
+
+	got, err := repo.GetByID(t.Context(), user.ID)
+	assert.NoError(t, err)
+	assert.True(t, got.IsAdmin)
+
+	got, err = repo.GetByID(t.Context(), 999)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	assert.Nil(t, got)
+}
+
+// TestUserRepo_AddDuplicate verifies that the unique username constraint
+// is reported as service.ErrConflict.
+func TestUserRepo_AddDuplicate(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepo(db)
+
+	assert.NoError(t, repo.Add(t.Context(), &service.User{Username: "david", Password: "a"}))
+	err := repo.Add(t.Context(), &service.User{Username: "david", Password: "b"})
+	assert.ErrorIs(t, err, service.ErrConflict)
 }
@@ -1,8 +1,12 @@
 package storage
 
 import (
+	"context"
+	"time"
+
 	"github.com/deividmendozatech-stack/wishlist/internal/service"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // WishlistRepo is the GORM-based implementation of service.WishlistRepository.
@@ -29,35 +33,156 @@ func NewWishlistRepo(db *gorm.DB) service.WishlistRepository {
 //
 // Returns:
 //   - error: any database error encountered during insertion
-func (r *WishlistRepo) Add(w *service.Wishlist) error {
-	return r.db.Create(w).Error
+func (r *WishlistRepo) Add(ctx context.Context, w *service.Wishlist) error {
+	return translateError(r.db.WithContext(ctx).Create(w).Error)
+}
+
+// wishlistPage describes how wishlists are sorted and filtered by ListPage.
+var wishlistPage = pageSpec[service.Wishlist]{
+	id: func(w *service.Wishlist) uint { return w.ID },
+	sorts: map[string]sortKey[service.Wishlist]{
+		service.SortCreated: {},
+		"name":              {column: "name", value: func(w *service.Wishlist) string { return w.Name }},
+	},
+	filters: map[string][]string{"name": {"name"}},
 }
 
-// List retrieves all wishlists belonging to a given user.
+// ListPage retrieves one page of the wishlists belonging to a given user.
 //
 // Params:
 //   - userID: the ID of the user
+//   - opts: normalized page, sort and filter options
 //
 // Returns:
-//   - []service.Wishlist: the list of wishlists
-//   - error: any database error encountered
-func (r *WishlistRepo) List(userID uint) ([]service.Wishlist, error) {
-	var wishlists []service.Wishlist
-	if err := r.db.Where("user_id = ?", userID).Find(&wishlists).Error; err != nil {
+//   - *service.Page[service.Wishlist]: the wishlists on the page and the next cursor
+//   - error: service.ErrValidation for an invalid cursor, or any database error
+func (r *WishlistRepo) ListPage(ctx context.Context, userID uint, opts service.ListOptions) (*service.Page[service.Wishlist], error) {
+	return paginate(r.db.WithContext(ctx).Model(&service.Wishlist{}).Where("user_id = ?", userID), wishlistPage, opts)
+}
+
+// Get retrieves a single wishlist by its ID, regardless of owner.
+//
+// Params:
+//   - wishlistID: the ID of the wishlist
+//
+// Returns:
+//   - *service.Wishlist: the matching wishlist
+//   - error: service.ErrNotFound if no wishlist matches, or any database error
+func (r *WishlistRepo) Get(ctx context.Context, wishlistID uint) (*service.Wishlist, error) {
+	var w service.Wishlist
+	if err := first(r.db.WithContext(ctx).Where("id = ?", wishlistID), &w); err != nil {
 		return nil, err
 	}
-	return wishlists, nil
+	return &w, nil
 }
 
-// Delete removes a wishlist by its ID and associated user ID.
+// Update persists the editable fields of an existing wishlist and sets w's
+// UpdatedAt to the stored update time.
+// The ID, owner, creation and deletion times and associations are never modified.
+//
+// Params:
+//   - w: pointer to the Wishlist entity with its new values
+//
+// Returns:
+//   - error: service.ErrNotFound if the wishlist does not exist, or any database error
+func (r *WishlistRepo) Update(ctx context.Context, w *service.Wishlist) error {
+	res := r.db.WithContext(ctx).Model(w).
+		Select("*").Omit("id", "user_id", "created_at", "deleted_at", clause.Associations).
+		Updates(w)
+	if res.Error != nil {
+		return translateError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// Delete soft-deletes a wishlist by its ID and associated user ID. Its
+// books are left as they are: they are hidden while the wishlist is deleted
+// and come back when it is restored.
 //
 // Params:
 //   - userID: the ID of the user who owns the wishlist
 //   - wishlistID: the ID of the wishlist to delete
 //
 // Returns:
-//   - error: any database error encountered during deletion
-func (r *WishlistRepo) Delete(userID, wishlistID uint) error {
-	return r.db.Where("id = ? AND user_id = ?", wishlistID, userID).
-		Delete(&service.Wishlist{}).Error
+//   - error: service.ErrNotFound if no live wishlist matched, or any database error
+func (r *WishlistRepo) Delete(ctx context.Context, userID, wishlistID uint) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", wishlistID, userID).
+		Delete(&service.Wishlist{})
+	if res.Error != nil {
+		return translateError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// ListDeleted retrieves the soft-deleted wishlists of a given user, most
+// recently deleted first.
+//
+// Params:
+//   - userID: the ID of the user
+//
+// Returns:
+//   - []service.Wishlist: the deleted wishlists
+//   - error: any database error encountered
+func (r *WishlistRepo) ListDeleted(ctx context.Context, userID uint) ([]service.Wishlist, error) {
+	var lists []service.Wishlist
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC, id DESC").
+		Find(&lists).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return lists, nil
+}
+
+// Restore clears the deletion time of a soft-deleted wishlist.
+//
+// Params:
+//   - userID: the ID of the user who owns the wishlist
+//   - wishlistID: the ID of the wishlist to restore
+//
+// Returns:
+//   - error: service.ErrNotFound if no deleted wishlist matched, or any database error
+func (r *WishlistRepo) Restore(ctx context.Context, userID, wishlistID uint) error {
+	res := r.db.WithContext(ctx).Unscoped().Model(&service.Wishlist{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", wishlistID, userID).
+		Update("deleted_at", nil)
+	if res.Error != nil {
+		return translateError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// Purge permanently deletes the wishlists soft-deleted before the given time,
+// together with all of their books, in a single transaction. The explicit
+// book deletion keeps tables created before the ON DELETE CASCADE constraint
+// consistent too.
+//
+// Params:
+//   - before: wishlists deleted earlier than this are purged
+//
+// Returns:
+//   - int64: number of purged wishlists
+//   - error: any database error encountered
+func (r *WishlistRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&service.Wishlist{}).Select("id").Where("deleted_at < ?", before.UTC())
+		if err := tx.Unscoped().Where("wishlist_id IN (?)", expired).Delete(&service.Book{}).Error; err != nil {
+			return err
+		}
+		res := tx.Unscoped().Where("deleted_at < ?", before.UTC()).Delete(&service.Wishlist{})
+		n = res.RowsAffected
+		return res.Error
+	})
+	return n, translateError(err)
 }
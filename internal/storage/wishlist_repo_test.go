@@ -2,6 +2,7 @@ package storage
 
 import (
 	"testing"
+	"time"
 
 	"github.com/deividmendozatech-stack/wishlist/internal/service"
 	"github.com/stretchr/testify/assert"
@@ -10,7 +11,7 @@ import (
 )
 
 // setupWishlistTestDB creates an in-memory SQLite database
-// and runs migrations for the Wishlist model.
+// and runs migrations for the Wishlist and Book models.
 //
 // Params:
 //   - t: testing context
@@ -22,7 +23,7 @@ func setupWishlistTestDB(t *testing.T) *gorm.DB {
 	if err != nil {
 		t.Fatalf("failed to open test db: %v", err)
 	}
-	if err := db.AutoMigrate(&service.Wishlist{}); err != nil {
+	if err := db.AutoMigrate(&service.Wishlist{}, &service.Book{}); err != nil {
 		t.Fatalf("failed to migrate: %v", err)
 	}
 	return db
@@ -36,21 +37,144 @@ func TestWishlistRepo_AddListDelete(t *testing.T) {
 
 	// Add a new wishlist
 	w := &service.Wishlist{UserID: 1, Name: "Mi lista"}
-	err := repo.Add(w)
+	err := repo.Add(t.Context(), w)
 	assert.NoError(t, err)
 
 	// List wishlists for user 1
-	lists, err := repo.List(1)
+	page, err := repo.ListPage(t.Context(), 1, service.ListOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, lists, 1)
-	assert.Equal(t, "Mi lista", lists[0].Name)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "Mi lista", page.Items[0].Name)
+	assert.Empty(t, page.NextCursor)
 
 	// Delete the wishlist
-	err = repo.Delete(1, lists[0].ID)
+	err = repo.Delete(t.Context(), 1, page.Items[0].ID)
 	assert.NoError(t, err)
 
 	// Verify that the list is now empty
-	lists, err = repo.List(1)
+	page, err = repo.ListPage(t.Context(), 1, service.ListOptions{})
 	assert.NoError(t, err)
-	assert.Empty(t, lists)
+	assert.Empty(t, page.Items)
+}
+
+// TestWishlistRepo_Get verifies lookups by ID regardless of owner,
+// including service.ErrNotFound for unknown IDs.
+func TestWishlistRepo_Get(t *testing.T) {
+	db := setupWishlistTestDB(t)
+	repo := NewWishlistRepo(db)
+
+	w := &service.Wishlist{UserID: 2, Name: "Otra lista"}
+	assert.NoError(t, repo.Add(t.Context(), w))
+
+	got, err := repo.Get(t.Context(), w.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, got)
+	assert.Equal(t, uint(2), got.UserID)
+
+	got, err = repo.Get(t.Context(), 999)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	assert.Nil(t, got)
+}
+
+// TestWishlistRepo_DeleteNotFound verifies that deleting a missing wishlist,
+// or one owned by another user, reports service.ErrNotFound.
+func TestWishlistRepo_DeleteNotFound(t *testing.T) {
+	db := setupWishlistTestDB(t)
+	repo := NewWishlistRepo(db)
+
+	w := &service.Wishlist{UserID: 1, Name: "Mi lista"}
+	assert.NoError(t, repo.Add(t.Context(), w))
+
+	// Nonexistent ID
+	assert.ErrorIs(t, repo.Delete(t.Context(), 1, 999), service.ErrNotFound)
+
+	// Existing wishlist, wrong owner
+	assert.ErrorIs(t, repo.Delete(t.Context(), 2, w.ID), service.ErrNotFound)
+
+	// Deleting twice: first succeeds, second reports not found
+	assert.NoError(t, repo.Delete(t.Context(), 1, w.ID))
+	assert.ErrorIs(t, repo.Delete(t.Context(), 1, w.ID), service.ErrNotFound)
+}
+
+// TestWishlistRepo_Trash verifies that deleting a wishlist hides it and its
+// books until it is restored, and that purging removes both for good while
+// other wishlists are untouched.
+func TestWishlistRepo_Trash(t *testing.T) {
+	db := setupWishlistTestDB(t)
+	repo := NewWishlistRepo(db)
+	books := NewBookRepo(db)
+
+	w1 := &service.Wishlist{UserID: 1, Name: "Uno"}
+	w2 := &service.Wishlist{UserID: 1, Name: "Dos"}
+	assert.NoError(t, repo.Add(t.Context(), w1))
+	assert.NoError(t, repo.Add(t.Context(), w2))
+	assert.False(t, w1.CreatedAt.IsZero())
+	assert.NoError(t, books.Add(t.Context(), &service.Book{WishlistID: w1.ID, Title: "A"}))
+	assert.NoError(t, books.Add(t.Context(), &service.Book{WishlistID: w1.ID, Title: "B"}))
+	assert.NoError(t, books.Add(t.Context(), &service.Book{WishlistID: w2.ID, Title: "C"}))
+
+	assert.NoError(t, repo.Delete(t.Context(), 1, w1.ID))
+	_, err := repo.Get(t.Context(), w1.ID)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+
+	deleted, err := repo.ListDeleted(t.Context(), 1)
+	assert.NoError(t, err)
+	if assert.Len(t, deleted, 1) {
+		assert.Equal(t, "Uno", deleted[0].Name)
+		assert.True(t, deleted[0].DeletedAt.Valid)
+	}
+	deleted, err = repo.ListDeleted(t.Context(), 2)
+	assert.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	// Restoring needs the owner, and only works on deleted wishlists
+	assert.ErrorIs(t, repo.Restore(t.Context(), 2, w1.ID), service.ErrNotFound)
+	assert.ErrorIs(t, repo.Restore(t.Context(), 1, w2.ID), service.ErrNotFound)
+	assert.NoError(t, repo.Restore(t.Context(), 1, w1.ID))
+	restored, err := books.List(t.Context(), w1.ID)
+	assert.NoError(t, err)
+	assert.Len(t, restored, 2)
+
+	// Only wishlists deleted before the cutoff are purged, with their books
+	assert.NoError(t, repo.Delete(t.Context(), 1, w1.ID))
+	n, err := repo.Purge(t.Context(), time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+	n, err = repo.Purge(t.Context(), time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	var count int64
+	db.Unscoped().Model(&service.Book{}).Where("wishlist_id = ?", w1.ID).Count(&count)
+	assert.Zero(t, count)
+	deleted, err = repo.ListDeleted(t.Context(), 1)
+	assert.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	remaining, err := books.List(t.Context(), w2.ID)
+	assert.NoError(t, err)
+	assert.Len(t, remaining, 1)
+}
+
+// TestWishlistRepo_Update verifies that a wishlist can be renamed without
+// changing its owner, and that updating a missing wishlist reports not found.
+func TestWishlistRepo_Update(t *testing.T) {
+	db := setupWishlistTestDB(t)
+	repo := NewWishlistRepo(db)
+
+	w := &service.Wishlist{UserID: 1, Name: "Mi lista"}
+	assert.NoError(t, repo.Add(t.Context(), w))
+
+	time.Sleep(time.Millisecond)
+	renamed := &service.Wishlist{ID: w.ID, UserID: 2, Name: "Renombrada"}
+	assert.NoError(t, repo.Update(t.Context(), renamed))
+	assert.True(t, renamed.UpdatedAt.After(w.UpdatedAt), "update time set on the passed value")
+	got, err := repo.Get(t.Context(), w.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Renombrada", got.Name)
+	assert.Equal(t, uint(1), got.UserID)
+	assert.False(t, got.CreatedAt.IsZero(), "creation time kept")
+	assert.True(t, got.UpdatedAt.Equal(renamed.UpdatedAt))
+
+	assert.ErrorIs(t, repo.Update(t.Context(), &service.Wishlist{ID: 999, Name: "X"}), service.ErrNotFound)
 }
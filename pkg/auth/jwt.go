@@ -0,0 +1,202 @@
+// Package auth provides JWT helpers used to authenticate API requests.
+//
+// Tokens are signed with HMAC-SHA256 (HS256) using a shared signing key.
+// Only the small subset of RFC 7519 needed by the Wishlist API is supported.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//
+// ─────────────────────────── ERRORS ───────────────────────────
+//
+
+// Predefined token validation errors.
+var (
+	// ErrMissingKey is returned when a Manager is built without a signing key.
+	ErrMissingKey = errors.New("auth: signing key cannot be empty")
+
+	// ErrInvalidToken is returned when a token is malformed or its signature does not match.
+	ErrInvalidToken = errors.New("auth: invalid token")
+
+	// ErrExpiredToken is returned when a token is past its expiration time.
+	ErrExpiredToken = errors.New("auth: token expired")
+
+	// ErrTokenNotYetValid is returned when a token is used before its not-before time.
+	ErrTokenNotYetValid = errors.New("auth: token not yet valid")
+)
+
+//
+// ─────────────────────────── CONFIG ───────────────────────────
+//
+
+// Default values applied by NewManager when the Config leaves them empty.
+const (
+	DefaultExpiry    = 24 * time.Hour
+	DefaultClockSkew = 30 * time.Second
+)
+
+// Config holds the settings used to issue and validate tokens.
+type Config struct {
+	SigningKey []byte        // HMAC key used to sign tokens (required)
+	Expiry     time.Duration // Token lifetime (default: DefaultExpiry)
+	ClockSkew  time.Duration // Tolerance for exp/nbf checks (default: DefaultClockSkew, negative disables)
+	Issuer     string        // Optional "iss" claim; checked on parse when set
+}
+
+// Claims is the JWT payload issued by the API.
+type Claims struct {
+	Subject   string `json:"sub"`                // User ID as a decimal string
+	Username  string `json:"username,omitempty"` // Username at the time of issue
+	Issuer    string `json:"iss,omitempty"`      // Token issuer
+	IssuedAt  int64  `json:"iat"`                // Unix time the token was issued
+	NotBefore int64  `json:"nbf"`                // Unix time before which the token is invalid
+	ExpiresAt int64  `json:"exp"`                // Unix time after which the token is invalid
+}
+
+// UserID returns the numeric user ID stored in the subject claim.
+func (c *Claims) UserID() (uint, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 64)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidToken
+	}
+	return uint(id), nil
+}
+
+//
+// ─────────────────────────── MANAGER ───────────────────────────
+//
+
+// header is the fixed JOSE header emitted for every token.
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+// encodedHeader is the base64url form of the HS256 header, computed once.
+var encodedHeader = encodeSegment(mustJSON(header{Alg: "HS256", Typ: "JWT"}))
+
+// Manager issues and validates signed tokens.
+type Manager struct {
+	cfg Config
+	now func() time.Time
+}
+
+// NewManager creates a Manager with the given configuration.
+// Returns ErrMissingKey if no signing key is provided.
+func NewManager(cfg Config) (*Manager, error) {
+	if len(cfg.SigningKey) == 0 {
+		return nil, ErrMissingKey
+	}
+	if cfg.Expiry <= 0 {
+		cfg.Expiry = DefaultExpiry
+	}
+	if cfg.ClockSkew < 0 {
+		cfg.ClockSkew = 0
+	} else if cfg.ClockSkew == 0 {
+		cfg.ClockSkew = DefaultClockSkew
+	}
+	return &Manager{cfg: cfg, now: time.Now}, nil
+}
+
+// Issue creates a signed token for the given user.
+// It returns the token string and its expiration time.
+func (m *Manager) Issue(userID uint, username string) (string, time.Time, error) {
+	now := m.now()
+	exp := now.Add(m.cfg.Expiry)
+	claims := Claims{
+		Subject:   strconv.FormatUint(uint64(userID), 10),
+		Username:  username,
+		Issuer:    m.cfg.Issuer,
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: exp.Unix(),
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	unsigned := encodedHeader + "." + encodeSegment(payload)
+	return unsigned + "." + m.sign(unsigned), exp, nil
+}
+
+// Parse verifies the token signature and time-based claims and returns its claims.
+// Expiration and not-before checks allow for the configured clock skew.
+func (m *Manager) Parse(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	// Only HS256 is accepted; this rejects "none" and algorithm confusion attacks.
+	var h header
+	if err := decodeJSONSegment(parts[0], &h); err != nil || h.Alg != "HS256" {
+		return nil, ErrInvalidToken
+	}
+
+	// Compare signatures in constant time
+	expected := m.sign(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := decodeJSONSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if m.cfg.Issuer != "" && claims.Issuer != m.cfg.Issuer {
+		return nil, ErrInvalidToken
+	}
+	if _, err := claims.UserID(); err != nil {
+		return nil, err
+	}
+
+	now := m.now()
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(m.cfg.ClockSkew)) {
+		return nil, ErrExpiredToken
+	}
+	if claims.NotBefore != 0 && now.Add(m.cfg.ClockSkew).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, ErrTokenNotYetValid
+	}
+	return &claims, nil
+}
+
+// sign returns the base64url HMAC-SHA256 signature of the given input.
+func (m *Manager) sign(input string) string {
+	mac := hmac.New(sha256.New, m.cfg.SigningKey)
+	mac.Write([]byte(input))
+	return encodeSegment(mac.Sum(nil))
+}
+
+//
+// ─────────────────────────── HELPERS ───────────────────────────
+//
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeJSONSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func mustJSON(v any) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestManager builds a Manager with a fixed clock for deterministic tests.
+func newTestManager(t *testing.T, cfg Config, now time.Time) *Manager {
+	t.Helper()
+	if cfg.SigningKey == nil {
+		cfg.SigningKey = []byte("test-secret")
+	}
+	m, err := NewManager(cfg)
+	if err != nil {
+		t.Fatalf("failed to build manager: %v", err)
+	}
+	m.now = func() time.Time { return now }
+	return m
+}
+
+// TestNewManager_MissingKey ensures a signing key is required.
+func TestNewManager_MissingKey(t *testing.T) {
+	_, err := NewManager(Config{})
+	assert.ErrorIs(t, err, ErrMissingKey)
+}
+
+// TestIssueAndParse verifies that an issued token round-trips its claims.
+func TestIssueAndParse(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	m := newTestManager(t, Config{Expiry: time.Hour, Issuer: "wishlist"}, now)
+
+	token, exp, err := m.Issue(42, "david")
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), exp)
+
+	claims, err := m.Parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "david", claims.Username)
+	id, err := claims.UserID()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(42), id)
+}
+
+// TestParse_Expiry checks that expired tokens are rejected, allowing for clock skew.
+func TestParse_Expiry(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	m := newTestManager(t, Config{Expiry: time.Hour, ClockSkew: time.Minute}, now)
+	token, _, _ := m.Issue(1, "david")
+
+	// Within the skew window the token is still accepted
+	m.now = func() time.Time { return now.Add(time.Hour + 30*time.Second) }
+	_, err := m.Parse(token)
+	assert.NoError(t, err)
+
+	// Past the skew window it is rejected
+	m.now = func() time.Time { return now.Add(time.Hour + 2*time.Minute) }
+	_, err = m.Parse(token)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
+
+// TestParse_NotYetValid checks the not-before claim against clock skew.
+func TestParse_NotYetValid(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	m := newTestManager(t, Config{ClockSkew: time.Minute}, now)
+	token, _, _ := m.Issue(1, "david")
+
+	m.now = func() time.Time { return now.Add(-30 * time.Second) }
+	_, err := m.Parse(token)
+	assert.NoError(t, err)
+
+	m.now = func() time.Time { return now.Add(-5 * time.Minute) }
+	_, err = m.Parse(token)
+	assert.ErrorIs(t, err, ErrTokenNotYetValid)
+}
+
+// TestParse_Invalid covers tampered, foreign and malformed tokens.
+func TestParse_Invalid(t *testing.T) {
+	now := time.Now()
+	m := newTestManager(t, Config{}, now)
+	other := newTestManager(t, Config{SigningKey: []byte("other-secret")}, now)
+
+	token, _, _ := m.Issue(1, "david")
+	foreign, _, _ := other.Issue(1, "david")
+	parts := strings.Split(token, ".")
+	noneAlg := encodeSegment([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + parts[1] + "."
+
+	for name, tok := range map[string]string{
+		"tampered": parts[0] + "." + parts[1] + "x." + parts[2],
+		"foreign":  foreign,
+		"none":     noneAlg,
+		"garbage":  "not-a-token",
+	} {
+		_, err := m.Parse(tok)
+		assert.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+}
+
+// TestMiddleware verifies that the middleware stores the user ID in the
+// request context and rejects requests without a valid token.
+func TestMiddleware(t *testing.T) {
+	m := newTestManager(t, Config{}, time.Now())
+	var gotID uint
+	h := Middleware(m)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID, _ = UserIDFromContext(r.Context())
+	}))
+
+	// Valid token
+	token, _, _ := m.Issue(9, "david")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, uint(9), gotID)
+
+	// Missing header
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.NotEmpty(t, resp.Header().Get("WWW-Authenticate"))
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+//
+// ─────────────────────────── CONTEXT ───────────────────────────
+//
+
+// ctxKey is an unexported type to avoid collisions with other context keys.
+type ctxKey struct{}
+
+// WithUserID returns a copy of ctx carrying the authenticated user ID.
+func WithUserID(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, ctxKey{}, userID)
+}
+
+// UserIDFromContext returns the authenticated user ID stored in ctx, if any.
+func UserIDFromContext(ctx context.Context) (uint, bool) {
+	id, ok := ctx.Value(ctxKey{}).(uint)
+	return id, ok && id != 0
+}
+
+//
+// ─────────────────────────── MIDDLEWARE ───────────────────────────
+//
+
+// Middleware validates the "Authorization: Bearer <token>" header and stores
+// the caller's user ID in the request context. Requests without a valid
+// token are rejected with 401 Unauthorized.
+func Middleware(m *Manager) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				unauthorized(w, "missing bearer token")
+				return
+			}
+			claims, err := m.Parse(token)
+			if err != nil {
+				unauthorized(w, err.Error())
+				return
+			}
+			userID, err := claims.UserID()
+			if err != nil {
+				unauthorized(w, err.Error())
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithUserID(r.Context(), userID)))
+		})
+	}
+}
+
+// bearerToken extracts the token from the Authorization header.
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(h, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// unauthorized writes a JSON 401 response with a Bearer challenge.
+// The body matches the API's {"error", "status"} error format.
+func unauthorized(w http.ResponseWriter, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="wishlist"`)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(map[string]any{"error": msg, "status": http.StatusUnauthorized})
+}